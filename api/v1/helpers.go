@@ -1,5 +1,7 @@
 package v1
 
+import "fmt"
+
 func FindFirstLocation(locations []LocationSpec, locationType LocationType) *LocationSpec {
 	for _, location := range locations {
 		if location.LocationType == locationType {
@@ -18,3 +20,105 @@ func FindAllLocations(locations []LocationSpec, locationType LocationType) []Loc
 	}
 	return result
 }
+
+// GetInstanceSpecs returns instance specs of all components keyed by the path of the spec field.
+func (s *YtsaurusSpec) GetInstanceSpecs() map[string]*InstanceSpec {
+	specs := map[string]*InstanceSpec{
+		"discovery":      &s.Discovery.InstanceSpec,
+		"primaryMasters": &s.PrimaryMasters.InstanceSpec,
+		"masterCaches":   &s.MasterCaches.InstanceSpec,
+	}
+	for i := range s.SecondaryMasters {
+		specs[fmt.Sprintf("secondaryMasters[%d]", i)] = &s.SecondaryMasters[i].InstanceSpec
+	}
+	for i := range s.HTTPProxies {
+		specs[fmt.Sprintf("httpProxies[%d]", i)] = &s.HTTPProxies[i].InstanceSpec
+	}
+	for i := range s.RPCProxies {
+		specs[fmt.Sprintf("rpcProxies[%d]", i)] = &s.RPCProxies[i].InstanceSpec
+	}
+	for i := range s.TCPProxies {
+		specs[fmt.Sprintf("tcpProxies[%d]", i)] = &s.TCPProxies[i].InstanceSpec
+	}
+	for i := range s.DataNodes {
+		specs[fmt.Sprintf("dataNodes[%d]", i)] = &s.DataNodes[i].InstanceSpec
+	}
+	for i := range s.ExecNodes {
+		specs[fmt.Sprintf("execNodes[%d]", i)] = &s.ExecNodes[i].InstanceSpec
+	}
+	for i := range s.TabletNodes {
+		specs[fmt.Sprintf("tabletNodes[%d]", i)] = &s.TabletNodes[i].InstanceSpec
+	}
+	if s.Schedulers != nil {
+		specs["schedulers"] = &s.Schedulers.InstanceSpec
+	}
+	if s.ControllerAgents != nil {
+		specs["controllerAgents"] = &s.ControllerAgents.InstanceSpec
+	}
+	if s.QueryTrackers != nil {
+		specs["queryTrackers"] = &s.QueryTrackers.InstanceSpec
+	}
+	if s.YQLAgents != nil {
+		specs["yqlAgents"] = &s.YQLAgents.InstanceSpec
+	}
+	if s.QueueAgents != nil {
+		specs["queueAgents"] = &s.QueueAgents.InstanceSpec
+	}
+	return specs
+}
+
+func (s *YtsaurusSpec) getOptionalImages() map[string]**string {
+	images := make(map[string]**string)
+	for path, instanceSpec := range s.GetInstanceSpecs() {
+		images[path+".image"] = &instanceSpec.Image
+	}
+	if s.UI != nil {
+		images["ui.image"] = &s.UI.Image
+	}
+	if s.StrawberryController != nil {
+		images["strawberry.image"] = &s.StrawberryController.Image
+	}
+	if s.DeprecatedChytController != nil {
+		images["chyt.image"] = &s.DeprecatedChytController.Image
+	}
+	return images
+}
+
+// GetImages returns all images of the spec keyed by the path of the spec field.
+func (s *YtsaurusSpec) GetImages() map[string]string {
+	images := map[string]string{
+		"coreImage": s.CoreImage,
+		"uiImage":   s.UIImage,
+	}
+	for path, image := range s.getOptionalImages() {
+		if *image != nil {
+			images[path] = **image
+		}
+	}
+	return images
+}
+
+// GetDataNodesInstanceCounts returns instance counts of the data nodes groups keyed by the group name.
+func (s *YtsaurusSpec) GetDataNodesInstanceCounts() map[string]int32 {
+	if len(s.DataNodes) == 0 {
+		return nil
+	}
+	counts := make(map[string]int32)
+	for _, spec := range s.DataNodes {
+		counts[spec.Name] = spec.InstanceCount
+	}
+	return counts
+}
+
+// SetImages sets images of the spec from the map returned by GetImages.
+func (s *YtsaurusSpec) SetImages(images map[string]string) {
+	s.CoreImage = images["coreImage"]
+	s.UIImage = images["uiImage"]
+	for path, image := range s.getOptionalImages() {
+		if value, ok := images[path]; ok {
+			*image = &value
+		} else {
+			*image = nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+	// Operator image may have no time zone database.
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func (s *MaintenanceWindowsSpec) getLocation() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.TimeZone)
+}
+
+func (w *MaintenanceWindowSpec) getSchedule() (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(w.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", w.Schedule, err)
+	}
+	return schedule, nil
+}
+
+// validate checks the time zone and the schedules of the windows.
+func (s *MaintenanceWindowsSpec) validate(path *field.Path) field.ErrorList {
+	var allErrors field.ErrorList
+
+	if _, err := s.getLocation(); err != nil {
+		allErrors = append(allErrors, field.Invalid(path.Child("timeZone"), s.TimeZone, err.Error()))
+	}
+
+	for i, window := range s.Windows {
+		windowPath := path.Child("windows").Index(i)
+		if _, err := window.getSchedule(); err != nil {
+			allErrors = append(allErrors, field.Invalid(windowPath.Child("schedule"), window.Schedule, err.Error()))
+		}
+		if window.Duration.Duration <= 0 {
+			allErrors = append(allErrors, field.Invalid(windowPath.Child("duration"), window.Duration, "duration must be positive"))
+		}
+	}
+
+	if len(s.Windows) == 0 {
+		allErrors = append(allErrors, field.Required(path.Child("windows"), "at least one window is required"))
+	}
+
+	return allErrors
+}
+
+// GetMaintenanceWindowState reports whether one of the windows is open at the given time,
+// otherwise it returns the start of the next window.
+func (s *MaintenanceWindowsSpec) GetMaintenanceWindowState(now time.Time) (bool, time.Time, error) {
+	location, err := s.getLocation()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	now = now.In(location)
+
+	var nextStart time.Time
+	for _, window := range s.Windows {
+		schedule, err := window.getSchedule()
+		if err != nil {
+			return false, time.Time{}, err
+		}
+
+		// The window is open if it has started after now-duration.
+		if start := schedule.Next(now.Add(-window.Duration.Duration)); !start.IsZero() && !start.After(now) {
+			return true, time.Time{}, nil
+		}
+
+		start := schedule.Next(now)
+		if !start.IsZero() && (nextStart.IsZero() || start.Before(nextStart)) {
+			nextStart = start
+		}
+	}
+	return false, nextStart, nil
+}
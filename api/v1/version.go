@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// YtsaurusVersion is the version of YTsaurus server components, e.g. 23.2.1.
+type YtsaurusVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ytsaurusVersionRegexp matches the version separated by dashes or underscores from the rest of the tag,
+// so dates and build numbers like "20240115.1" are not taken for versions.
+var ytsaurusVersionRegexp = regexp.MustCompile(`^(?:.*[-_])?(\d{1,3})\.(\d+)(?:\.(\d+))?(?:[-_+].*)?$`)
+
+// ParseYtsaurusVersion parses the version from the string, e.g. from the image tag "stable-23.2.0-relwithdebinfo".
+func ParseYtsaurusVersion(s string) (*YtsaurusVersion, error) {
+	match := ytsaurusVersionRegexp.FindStringSubmatch(s)
+	if match == nil {
+		return nil, fmt.Errorf("no version found in %q", s)
+	}
+
+	version := &YtsaurusVersion{}
+	version.Major, _ = strconv.Atoi(match[1])
+	version.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		version.Patch, _ = strconv.Atoi(match[3])
+	}
+	return version, nil
+}
+
+// GetImageVersion parses the version from the image tag.
+func GetImageVersion(image string) (*YtsaurusVersion, error) {
+	// Registry may contain port, so tag is looked for after the last slash.
+	name := image[strings.LastIndex(image, "/")+1:]
+	if digest := strings.Index(name, "@"); digest >= 0 {
+		name = name[:digest]
+	}
+	colon := strings.LastIndex(name, ":")
+	if colon < 0 {
+		return nil, fmt.Errorf("image %q has no tag", image)
+	}
+	return ParseYtsaurusVersion(name[colon+1:])
+}
+
+func (v YtsaurusVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 if the version is less than, equal to or greater than the other one.
+func (v YtsaurusVersion) Compare(other YtsaurusVersion) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// separatelyVersionedComponents are the components released in their own images with their own versions,
+// e.g. ytsaurus/query-tracker:0.0.1, so their versions are not related to the core ones.
+var separatelyVersionedComponents = map[string]bool{
+	"queryTrackers": true,
+	"yqlAgents":     true,
+	"queueAgents":   true,
+}
+
+// GetCoreVersions returns versions of the server components built from the core image keyed by the path of the instance spec.
+// Version of a component with its own image is parsed from the image tag, otherwise
+// CoreVersion is used if set or the version is parsed from CoreImage.
+// Separately versioned components with their own images and components with unknown versions are skipped.
+func (s *YtsaurusSpec) GetCoreVersions() map[string]YtsaurusVersion {
+	coreVersion, err := ParseYtsaurusVersion(s.CoreVersion)
+	if s.CoreVersion == "" || err != nil {
+		coreVersion, _ = GetImageVersion(s.CoreImage)
+	}
+
+	versions := make(map[string]YtsaurusVersion)
+	for path, instanceSpec := range s.GetInstanceSpecs() {
+		version := coreVersion
+		if instanceSpec.Image != nil {
+			if separatelyVersionedComponents[path] {
+				continue
+			}
+			version, _ = GetImageVersion(*instanceSpec.Image)
+		}
+		if version != nil {
+			versions[path] = *version
+		}
+	}
+	return versions
+}
+
+// ValidateCoreVersions checks that the components can be switched from the old versions to the new ones:
+// versions can't be downgraded, major versions can't be skipped
+// and all components should have the same major version as primary masters.
+func ValidateCoreVersions(oldVersions, newVersions map[string]YtsaurusVersion) field.ErrorList {
+	var allErrors field.ErrorList
+
+	paths := make([]string, 0, len(newVersions))
+	for path := range newVersions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		newVersion := newVersions[path]
+		fieldPath := field.NewPath("spec").Child(path)
+
+		if oldVersion, ok := oldVersions[path]; ok {
+			if newVersion.Compare(oldVersion) < 0 {
+				allErrors = append(allErrors, field.Forbidden(
+					fieldPath,
+					fmt.Sprintf("downgrade from version %s to %s is not supported", oldVersion, newVersion)))
+			} else if newVersion.Major > oldVersion.Major+1 {
+				allErrors = append(allErrors, field.Forbidden(
+					fieldPath,
+					fmt.Sprintf("update from version %s to %s skips major version %d", oldVersion, newVersion, oldVersion.Major+1)))
+			}
+		}
+
+		if masterVersion, ok := newVersions["primaryMasters"]; ok && newVersion.Major != masterVersion.Major {
+			allErrors = append(allErrors, field.Forbidden(
+				fieldPath,
+				fmt.Sprintf("version %s is incompatible with primary masters version %s", newVersion, masterVersion)))
+		}
+	}
+
+	return allErrors
+}
+
+// GetRunningCoreVersions returns versions of the server components the cluster is running with,
+// they are evaluated from the images saved in the status.
+func (r *Ytsaurus) GetRunningCoreVersions() map[string]YtsaurusVersion {
+	if len(r.Status.RunningImages) == 0 {
+		return nil
+	}
+
+	spec := r.Spec.DeepCopy()
+	spec.SetImages(r.Status.RunningImages)
+	if spec.CoreImage != r.Spec.CoreImage {
+		// Explicit version refers to the new core image.
+		spec.CoreVersion = ""
+	}
+	return spec.GetCoreVersions()
+}
@@ -19,6 +19,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -88,6 +89,17 @@ type LocationSpec struct {
 	Medium string `json:"medium,omitempty"`
 }
 
+// DeletionPolicy describes what happens with the persistent volume claims of the cluster on its deletion.
+// +enum
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the volume claims of masters and data nodes.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete removes the volume claims of masters and data nodes after their pods are removed.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+)
+
 // LogLevel string describes possible Ytsaurus logging level.
 // +enum
 type LogLevel string
@@ -211,6 +223,27 @@ type OauthServiceSpec struct {
 	UserInfo OauthUserInfoHandlerSpec `json:"userInfoHandler,omitempty"`
 }
 
+// ComponentUpdateStrategy string describes how pods of a component are replaced during a local update.
+// +enum
+type ComponentUpdateStrategy string
+
+const (
+	// All pods of the component are removed and created again.
+	ComponentUpdateStrategyBulkUpdate ComponentUpdateStrategy = "BulkUpdate"
+	// Pods are replaced one by one via StatefulSet partitions,
+	// each new pod has to become ready before the next one is replaced.
+	ComponentUpdateStrategyRollingUpdate ComponentUpdateStrategy = "RollingUpdate"
+)
+
+// PodDisruptionBudgetSpec limits voluntary evictions of the component pods, e.g. by a node drain.
+// At most one of minAvailable and maxUnavailable may be set.
+type PodDisruptionBudgetSpec struct {
+	//+optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	//+optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
 type InstanceSpec struct {
 	Image                 *string                         `json:"image,omitempty"`
 	Volumes               []corev1.Volume                 `json:"volumes,omitempty"`
@@ -232,6 +265,31 @@ type InstanceSpec struct {
 	// Component config for native RPC bus transport.
 	//+optional
 	NativeTransport *RPCTransportSpec `json:"nativeTransport,omitempty"`
+	// Strategy of pods replacement during a local update of the component.
+	// Components which require a full update (e.g. masters) are always updated in bulk,
+	// data and tablet nodes too since they keep chunk replicas and tablet cells.
+	//+kubebuilder:default:=BulkUpdate
+	//+kubebuilder:validation:Enum=BulkUpdate;RollingUpdate
+	//+optional
+	UpdateStrategy ComponentUpdateStrategy `json:"updateStrategy,omitempty"`
+	// Pods created before this time are restarted by the local update of the component, masters are restarted by the full update.
+	// Set it to the current time to restart the component without changing the image or config.
+	//+optional
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty"`
+	// Set to false to take over the component by hand: the operator stops syncing and updating it,
+	// and the components depending on it consider it running. The component is managed by default.
+	//+optional
+	Managed *bool `json:"managed,omitempty"`
+	// Readiness probe of the server container, by default the orchid is requested on the monitoring port.
+	//+optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// Liveness probe of the server container, by default the orchid is requested on the monitoring port.
+	//+optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// Pod disruption budget of the component. By default masters keep the quorum
+	// and a single pod of other components may be evicted at once.
+	//+optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 type MastersSpec struct {
@@ -323,6 +381,10 @@ type ClusterNodesSpec struct {
 	Tags []string `json:"tags,omitempty"`
 	// Name of the node rack.
 	Rack string `json:"rack,omitempty"`
+	// Wait until the nodes are registered in //sys/cluster_nodes with the online state
+	// before the component is considered ready.
+	//+optional
+	WaitForOnlineState bool `json:"waitForOnlineState,omitempty"`
 }
 
 type DataNodesSpec struct {
@@ -348,6 +410,11 @@ type ExecNodesSpec struct {
 	//+optional
 	Privileged      bool             `json:"privileged"`
 	JobProxyLoggers []TextLoggerSpec `json:"jobProxyLoggers,omitempty"`
+	// DrainTimeout enables draining of the exec nodes before their pods are removed during a local update.
+	// Scheduler jobs are disabled on the nodes and running jobs are given up to this time to finish,
+	// the remaining jobs are aborted together with the pods.
+	//+optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
 }
 
 type TabletNodesSpec struct {
@@ -440,7 +507,12 @@ type DeprecatedSpytSpec struct {
 // YtsaurusSpec defines the desired state of Ytsaurus
 type YtsaurusSpec struct {
 	CoreImage string `json:"coreImage,omitempty"`
-	UIImage   string `json:"uiImage,omitempty"`
+	// Version of YTsaurus in the core image, e.g. 23.2.0.
+	// If not set, the version is parsed from the image tag.
+	// Versions are used to reject downgrades and incompatible updates.
+	//+optional
+	CoreVersion string `json:"coreVersion,omitempty"`
+	UIImage     string `json:"uiImage,omitempty"`
 
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 	ConfigOverrides  *corev1.LocalObjectReference  `json:"configOverrides,omitempty"`
@@ -462,6 +534,43 @@ type YtsaurusSpec struct {
 	//+kubebuilder:default:=true
 	//+optional
 	EnableFullUpdate bool `json:"enableFullUpdate"`
+	// Hibernate shuts the running cluster down the same way as the full update does: safe mode is enabled,
+	// tablet cells are removed, read-only master snapshots are built and all the components are scaled to zero
+	// keeping their persistent volumes. The cluster is brought back up when it is set back to false.
+	//+optional
+	Hibernate bool `json:"hibernate,omitempty"`
+	// Update states which require manual approval before the operator proceeds with them.
+	// The update is paused at the beginning of each listed state until the ytsaurus.tech/approved-update-state
+	// annotation with the name of the state is set on the resource.
+	//+optional
+	UpdateApprovalStates []UpdateState `json:"updateApprovalStates,omitempty"`
+	// Deadlines of update states. The update is considered stuck when a state lasts longer than its timeout.
+	//+optional
+	UpdateStateTimeouts []UpdateStateTimeout `json:"updateStateTimeouts,omitempty"`
+	// Roll the stuck update back: restore previous images, recreate tablet cells and disable safe mode.
+	//+kubebuilder:default:=false
+	//+optional
+	RollbackStuckUpdate bool `json:"rollbackStuckUpdate"`
+	// Checks which are run before the full update, all built-in checks are enabled by default.
+	//+optional
+	UpdatePossibilityChecks *UpdatePossibilityChecksSpec `json:"updatePossibilityChecks,omitempty"`
+	// Windows when updates are allowed to start, updates start right away if not set.
+	//+optional
+	MaintenanceWindows *MaintenanceWindowsSpec `json:"maintenanceWindows,omitempty"`
+	// Period of the health checks of the running cluster, 1 minute by default. Zero period disables the checks.
+	// The cluster is checked by the checks enabled in updatePossibilityChecks.
+	//+optional
+	HealthCheckPeriod *metav1.Duration `json:"healthCheckPeriod,omitempty"`
+	// What happens with the volume claims of masters and data nodes created from volumeClaimTemplates
+	// when the cluster is deleted.
+	//+kubebuilder:default:=Retain
+	//+kubebuilder:validation:Enum=Retain;Delete
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Reject the deletion of the cluster, the protection has to be disabled before the cluster is deleted.
+	//+kubebuilder:default:=false
+	//+optional
+	DeletionProtection bool `json:"deletionProtection"`
 
 	//+kubebuilder:default:=false
 	//+optional
@@ -525,6 +634,65 @@ type RackAwarenessSpec struct {
 	DCLabel string `json:"dcLabel,omitempty"`
 }
 
+// CypressAssertionSpec requires the Cypress node to have the given value.
+type CypressAssertionSpec struct {
+	// Name of the check used in the update condition.
+	//+kubebuilder:validation:Pattern:=^[A-Za-z][A-Za-z0-9]*$
+	Name string `json:"name"`
+	// Path of the Cypress node, e.g. //sys/@config/chunk_manager/enable_chunk_replicator.
+	//+kubebuilder:validation:MinLength:=1
+	Path string `json:"path"`
+	// Expected value of the node in JSON.
+	Value string `json:"value"`
+}
+
+type MaintenanceWindowSpec struct {
+	// Cron expression of the window start, e.g. "0 2 * * SAT".
+	Schedule string          `json:"schedule"`
+	Duration metav1.Duration `json:"duration"`
+}
+
+type MaintenanceWindowsSpec struct {
+	// IANA time zone of the schedules, e.g. "Europe/Amsterdam".
+	//+kubebuilder:default:=UTC
+	//+optional
+	TimeZone string                  `json:"timeZone,omitempty"`
+	Windows  []MaintenanceWindowSpec `json:"windows"`
+}
+
+type UpdatePossibilityChecksSpec struct {
+	// All tablet cell bundles except the allowed degraded ones have to be in good health.
+	//+kubebuilder:default:=true
+	//+optional
+	TabletCellBundlesHealth bool `json:"tabletCellBundlesHealth"`
+	// Bundles which are allowed to be not in good health.
+	//+optional
+	AllowedDegradedBundles []string `json:"allowedDegradedBundles,omitempty"`
+	// There must be no lost vital chunks.
+	//+kubebuilder:default:=true
+	//+optional
+	LostVitalChunks bool `json:"lostVitalChunks"`
+	// There must be no quorum missing chunks.
+	//+kubebuilder:default:=true
+	//+optional
+	QuorumMissingChunks bool `json:"quorumMissingChunks"`
+	// All masters have to be active and each cell must have a leader.
+	//+kubebuilder:default:=true
+	//+optional
+	MastersHydra bool `json:"mastersHydra"`
+	// Maximum allowed count of data missing chunks, not checked if unset.
+	//+kubebuilder:validation:Minimum:=0
+	//+optional
+	MaxDataMissingChunks *int `json:"maxDataMissingChunks,omitempty"`
+	// There must be no master alerts.
+	//+kubebuilder:default:=false
+	//+optional
+	NoMasterAlerts bool `json:"noMasterAlerts"`
+	// Arbitrary assertions on Cypress node values.
+	//+optional
+	CypressAssertions []CypressAssertionSpec `json:"cypressAssertions,omitempty"`
+}
+
 type JobsSpec struct {
 	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
 	Affinity     *corev1.Affinity    `json:"affinity,omitempty"`
@@ -541,12 +709,20 @@ const (
 	ClusterStateUpdating        ClusterState = "Updating"
 	ClusterStateUpdateFinishing ClusterState = "UpdateFinishing"
 	ClusterStateCancelUpdate    ClusterState = "CancelUpdate"
+	ClusterStateHibernating     ClusterState = "Hibernating"
+	ClusterStateHibernated      ClusterState = "Hibernated"
 )
 
 func IsReadyToUpdateClusterState(clusterState ClusterState) bool {
 	return clusterState == ClusterStateRunning
 }
 
+// IsUpdatingClusterState checks whether the components follow the update state,
+// hibernation runs the steps of the full update as well.
+func IsUpdatingClusterState(clusterState ClusterState) bool {
+	return clusterState == ClusterStateUpdating || clusterState == ClusterStateHibernating
+}
+
 type UpdateState string
 
 const (
@@ -569,6 +745,11 @@ const (
 	UpdateStateWaitingForSafeModeDisabled         UpdateState = "WaitingForSafeModeDisabled"
 )
 
+type UpdateStateTimeout struct {
+	State   UpdateState     `json:"state"`
+	Timeout metav1.Duration `json:"timeout"`
+}
+
 type TabletCellBundleInfo struct {
 	Name            string `yson:",value" json:"name"`
 	TabletCellCount int    `yson:"tablet_cell_count,attr" json:"tabletCellCount"`
@@ -581,6 +762,130 @@ type UpdateStatus struct {
 	Conditions            []metav1.Condition     `json:"conditions,omitempty"`
 	TabletCellBundles     []TabletCellBundleInfo `json:"tabletCellBundles,omitempty"`
 	MasterMonitoringPaths []string               `json:"masterMonitoringPaths,omitempty"`
+	// Time when the current update state was entered.
+	StateStartTime *metav1.Time `json:"stateStartTime,omitempty"`
+}
+
+type UpdateMode string
+
+const (
+	UpdateModeFull  UpdateMode = "Full"
+	UpdateModeLocal UpdateMode = "Local"
+)
+
+type UpdateOutcome string
+
+const (
+	UpdateOutcomeFinished   UpdateOutcome = "Finished"
+	UpdateOutcomeCancelled  UpdateOutcome = "Cancelled"
+	UpdateOutcomeImpossible UpdateOutcome = "Impossible"
+)
+
+type UpdateStateDuration struct {
+	State    UpdateState     `json:"state"`
+	Duration metav1.Duration `json:"duration"`
+}
+
+// UpdateHistoryEntry describes a single update of the cluster.
+type UpdateHistoryEntry struct {
+	StartTime metav1.Time `json:"startTime"`
+	//+optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	Mode    UpdateMode   `json:"mode"`
+	// Components updated in the local mode.
+	//+optional
+	Components []string `json:"components,omitempty"`
+	// Reasons of the update keyed by the component name, e.g. changed image or requested restart.
+	//+optional
+	Reasons map[string]string `json:"reasons,omitempty"`
+	// Images of the cluster before and after the update, keyed by the path of the spec field.
+	//+optional
+	OldImages map[string]string `json:"oldImages,omitempty"`
+	//+optional
+	NewImages map[string]string `json:"newImages,omitempty"`
+	// Time spent in each update state.
+	//+optional
+	StateDurations []UpdateStateDuration `json:"stateDurations,omitempty"`
+	// Outcome of the update, empty while the update is in progress.
+	//+optional
+	Outcome UpdateOutcome `json:"outcome,omitempty"`
+	//+optional
+	Message string `json:"message,omitempty"`
+}
+
+type ComponentUpdatePlan struct {
+	Name string `json:"name"`
+	// Sync status of the component, NeedFullUpdate or NeedLocalUpdate.
+	SyncStatus string `json:"syncStatus"`
+	// Why the component needs update, e.g. image or config change.
+	//+optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// UpdatePlan describes the update which would be performed for the current spec.
+type UpdatePlan struct {
+	CreationTime metav1.Time `json:"creationTime"`
+	Mode         UpdateMode  `json:"mode"`
+	//+optional
+	Components []ComponentUpdatePlan `json:"components,omitempty"`
+	// Update states which will be run, in order.
+	//+optional
+	States []UpdateState `json:"states,omitempty"`
+	//+optional
+	Message string `json:"message,omitempty"`
+}
+
+// DataNodesScaleDown describes the decommission of data nodes removed from the spec.
+type DataNodesScaleDown struct {
+	// Name of the data nodes component.
+	Component string `json:"component"`
+	// Instance count the component is scaled down to.
+	InstanceCount int32 `json:"instanceCount"`
+	// Addresses of the decommissioned nodes.
+	//+optional
+	Nodes []string `json:"nodes,omitempty"`
+	// Counts of lost and underreplicated chunks before the decommission,
+	// the statefulset is shrunk only when the counts don't exceed them.
+	LostChunks            int64       `json:"lostChunks"`
+	UnderreplicatedChunks int64       `json:"underreplicatedChunks"`
+	StartTime             metav1.Time `json:"startTime"`
+}
+
+// ComponentDependencies describes the components a component waits for.
+type ComponentDependencies struct {
+	Name string `json:"name"`
+	//+optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Dependencies which are not running yet while the component is blocked.
+	//+optional
+	BlockedBy []string `json:"blockedBy,omitempty"`
+}
+
+// ComponentStatus is the observed state of a component.
+type ComponentStatus struct {
+	Name string `json:"name"`
+	// Kind of the component, e.g. DataNode for all groups of data nodes.
+	Kind       string `json:"kind"`
+	SyncStatus string `json:"syncStatus"`
+	//+optional
+	Message string `json:"message,omitempty"`
+
+	//+optional
+	DesiredImage string `json:"desiredImage,omitempty"`
+	//+optional
+	ObservedImage string `json:"observedImage,omitempty"`
+	//+optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	//+optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	//+optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+	// Hash of the configs the pods are running with.
+	//+optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// Last time the sync status of the component changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
 }
 
 // YtsaurusStatus defines the observed state of Ytsaurus
@@ -590,6 +895,32 @@ type YtsaurusStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	UpdateStatus UpdateStatus `json:"updateStatus,omitempty"`
+
+	// Images of the spec the cluster was running with last time, keyed by the path of the spec field.
+	// They are restored on the rollback of the stuck update.
+	RunningImages map[string]string `json:"runningImages,omitempty"`
+
+	// Instance counts of the data nodes groups the cluster was running with last time, keyed by the group name.
+	// The nodes above the instance count of the spec are decommissioned before the statefulset is shrunk.
+	RunningDataNodesInstanceCounts map[string]int32 `json:"runningDataNodesInstanceCounts,omitempty"`
+
+	// Last updates of the cluster, the latest one goes last.
+	UpdateHistory []UpdateHistoryEntry `json:"updateHistory,omitempty"`
+
+	// Plan of the pending update, computed when the update plan only annotation is set.
+	UpdatePlan *UpdatePlan `json:"updatePlan,omitempty"`
+
+	// Data nodes scale-downs in progress.
+	DataNodesScaleDowns []DataNodesScaleDown `json:"dataNodesScaleDowns,omitempty"`
+
+	// Dependencies of the components in the order they are synced.
+	ComponentDependencies []ComponentDependencies `json:"componentDependencies,omitempty"`
+
+	// Observed state of the components in the order they are synced.
+	Components []ComponentStatus `json:"components,omitempty"`
+
+	// Time of the last health check of the running cluster.
+	LastHealthCheckTime *metav1.Time `json:"lastHealthCheckTime,omitempty"`
 }
 
 //+kubebuilder:rbac:groups=cluster.ytsaurus.tech,resources=ytsaurus,verbs=get;list;watch;create;update;patch;delete
@@ -601,14 +932,19 @@ type YtsaurusStatus struct {
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="ClusterState",type="string",JSONPath=".status.state",description="State of Ytsaurus cluster"
 // +kubebuilder:printcolumn:name="UpdateState",type="string",JSONPath=".status.updateStatus.state",description="Update state of Ytsaurus cluster"
+// +kubebuilder:printcolumn:name="Healthy",type="string",JSONPath=".status.conditions[?(@.type==\"Healthy\")].status",description="Health of Ytsaurus cluster"
 // +kubebuilder:printcolumn:name="UpdatingComponents",type="string",JSONPath=".status.updateStatus.components",description="Updating components (for local update)"
+// +kubebuilder:printcolumn:name="BlockedComponents",type="string",JSONPath=".status.components[?(@.syncStatus==\"Blocked\")].name",description="Blocked components",priority=1
+// +kubebuilder:printcolumn:name="BlockedBy",type="string",JSONPath=".status.components[?(@.syncStatus==\"Blocked\")].message",description="Reasons of blocked components",priority=1
 // +kubebuilder:resource:shortName=yt,singular=ytsaurus,path=ytsaurus
 // +kubebuilder:subresource:status
 
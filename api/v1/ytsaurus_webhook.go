@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -73,8 +74,7 @@ func (r *Ytsaurus) Default() {
 	}
 }
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
-//+kubebuilder:webhook:path=/validate-cluster-ytsaurus-tech-v1-ytsaurus,mutating=false,failurePolicy=fail,sideEffects=None,groups=cluster.ytsaurus.tech,resources=ytsaurus,verbs=create;update,versions=v1,name=vytsaurus.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-cluster-ytsaurus-tech-v1-ytsaurus,mutating=false,failurePolicy=fail,sideEffects=None,groups=cluster.ytsaurus.tech,resources=ytsaurus,verbs=create;update;delete,versions=v1,name=vytsaurus.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Validator = &Ytsaurus{}
 
@@ -94,6 +94,7 @@ func (r *Ytsaurus) validatePrimaryMasters(old *runtime.Object) field.ErrorList {
 	path := field.NewPath("spec").Child("primaryMasters")
 	allErrors = append(allErrors, r.validateInstanceSpec(r.Spec.PrimaryMasters.InstanceSpec, path)...)
 	allErrors = append(allErrors, r.validateHostAddresses(r.Spec.PrimaryMasters, path)...)
+	allErrors = append(allErrors, validateBulkUpdateStrategy(r.Spec.PrimaryMasters.UpdateStrategy, path)...)
 
 	if FindFirstLocation(r.Spec.PrimaryMasters.Locations, LocationTypeMasterChangelogs) == nil {
 		allErrors = append(allErrors, field.NotFound(path.Child("locations"), LocationTypeMasterChangelogs))
@@ -121,6 +122,27 @@ func (r *Ytsaurus) validateSecondaryMasters(old *runtime.Object) field.ErrorList
 		path := field.NewPath("spec").Child("secondaryMasters").Index(i)
 		allErrors = append(allErrors, r.validateInstanceSpec(sm.InstanceSpec, path)...)
 		allErrors = append(allErrors, r.validateHostAddresses(r.Spec.PrimaryMasters, path)...)
+		allErrors = append(allErrors, validateBulkUpdateStrategy(sm.UpdateStrategy, path)...)
+	}
+
+	return allErrors
+}
+
+// validateBulkUpdateStrategy rejects the rolling update for the components which keep the cluster state:
+// masters are updated only by the full update, and replacing data or tablet nodes one by one
+// would move chunk replicas and tablet cells across the nodes on every step.
+func validateBulkUpdateStrategy(updateStrategy ComponentUpdateStrategy, fieldPath *field.Path) field.ErrorList {
+	var allErrors field.ErrorList
+
+	if updateStrategy == ComponentUpdateStrategyRollingUpdate {
+		allErrors = append(
+			allErrors,
+			field.NotSupported(
+				fieldPath.Child("updateStrategy"),
+				updateStrategy,
+				[]string{string(ComponentUpdateStrategyBulkUpdate)},
+			),
+		)
 	}
 
 	return allErrors
@@ -246,6 +268,7 @@ func (r *Ytsaurus) validateDataNodes(old *runtime.Object) field.ErrorList {
 		names[dn.Name] = true
 
 		allErrors = append(allErrors, r.validateInstanceSpec(dn.InstanceSpec, path)...)
+		allErrors = append(allErrors, validateBulkUpdateStrategy(dn.UpdateStrategy, path)...)
 
 		if FindFirstLocation(dn.Locations, LocationTypeChunkStore) == nil {
 			allErrors = append(allErrors, field.NotFound(path.Child("locations"), LocationTypeChunkStore))
@@ -298,6 +321,10 @@ func (r *Ytsaurus) validateExecNodes(old *runtime.Object) field.ErrorList {
 		if en.Sidecars != nil {
 			allErrors = append(allErrors, validateSidecars(en.Sidecars, path.Child("sidecars"))...)
 		}
+
+		if en.DrainTimeout != nil && en.DrainTimeout.Duration <= 0 {
+			allErrors = append(allErrors, field.Invalid(path.Child("drainTimeout"), en.DrainTimeout, "timeout must be positive"))
+		}
 	}
 
 	if r.Spec.ExecNodes != nil && len(r.Spec.ExecNodes) > 0 {
@@ -352,6 +379,7 @@ func (r *Ytsaurus) validateTabletNodes(old *runtime.Object) field.ErrorList {
 		names[tn.Name] = true
 
 		allErrors = append(allErrors, r.validateInstanceSpec(tn.InstanceSpec, path)...)
+		allErrors = append(allErrors, validateBulkUpdateStrategy(tn.UpdateStrategy, path)...)
 	}
 
 	return allErrors
@@ -435,6 +463,95 @@ func (r *Ytsaurus) validateYQLAgents(old *runtime.Object) field.ErrorList {
 	return allErrors
 }
 
+func isKnownUpdateState(state UpdateState) bool {
+	switch state {
+	case UpdateStateNone,
+		UpdateStatePossibilityCheck,
+		UpdateStateImpossibleToStart,
+		UpdateStateWaitingForSafeModeEnabled,
+		UpdateStateWaitingForTabletCellsSaving,
+		UpdateStateWaitingForTabletCellsRemovingStart,
+		UpdateStateWaitingForTabletCellsRemoved,
+		UpdateStateWaitingForSnapshots,
+		UpdateStateWaitingForPodsRemoval,
+		UpdateStateWaitingForPodsCreation,
+		UpdateStateWaitingForMasterExitReadOnly,
+		UpdateStateWaitingForTabletCellsRecovery,
+		UpdateStateWaitingForOpArchiveUpdatingPrepare,
+		UpdateStateWaitingForOpArchiveUpdate,
+		UpdateStateWaitingForQTStateUpdatingPrepare,
+		UpdateStateWaitingForQTStateUpdate,
+		UpdateStateWaitingForSafeModeDisabled:
+		return true
+	}
+	return false
+}
+
+func (r *Ytsaurus) validateUpdateApprovalStates(old *runtime.Object) field.ErrorList {
+	var allErrors field.ErrorList
+	path := field.NewPath("spec").Child("updateApprovalStates")
+
+	for i, state := range r.Spec.UpdateApprovalStates {
+		if !isKnownUpdateState(state) {
+			allErrors = append(allErrors, field.NotSupported(path.Index(i), state, nil))
+		} else if state == UpdateStateNone || state == UpdateStateImpossibleToStart {
+			allErrors = append(allErrors, field.Invalid(path.Index(i), state, "state can't require approval"))
+		}
+	}
+
+	return allErrors
+}
+
+func (r *Ytsaurus) validateUpdateStateTimeouts(old *runtime.Object) field.ErrorList {
+	var allErrors field.ErrorList
+	path := field.NewPath("spec").Child("updateStateTimeouts")
+
+	states := make(map[UpdateState]bool)
+	for i, stateTimeout := range r.Spec.UpdateStateTimeouts {
+		if !isKnownUpdateState(stateTimeout.State) {
+			allErrors = append(allErrors, field.NotSupported(path.Index(i).Child("state"), stateTimeout.State, nil))
+		}
+		if states[stateTimeout.State] {
+			allErrors = append(allErrors, field.Duplicate(path.Index(i).Child("state"), stateTimeout.State))
+		}
+		states[stateTimeout.State] = true
+
+		if stateTimeout.Timeout.Duration <= 0 {
+			allErrors = append(allErrors, field.Invalid(path.Index(i).Child("timeout"), stateTimeout.Timeout, "timeout must be positive"))
+		}
+	}
+
+	return allErrors
+}
+
+func (r *Ytsaurus) validateUpdatePossibilityChecks(old *runtime.Object) field.ErrorList {
+	var allErrors field.ErrorList
+
+	if r.Spec.UpdatePossibilityChecks == nil {
+		return allErrors
+	}
+
+	path := field.NewPath("spec").Child("updatePossibilityChecks").Child("cypressAssertions")
+	names := make(map[string]bool)
+	for i, assertion := range r.Spec.UpdatePossibilityChecks.CypressAssertions {
+		if names[assertion.Name] {
+			allErrors = append(allErrors, field.Duplicate(path.Index(i).Child("name"), assertion.Name))
+		}
+		names[assertion.Name] = true
+
+		if !strings.HasPrefix(assertion.Path, "//") {
+			allErrors = append(allErrors, field.Invalid(path.Index(i).Child("path"), assertion.Path, "path must be absolute Cypress path"))
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(assertion.Value), &value); err != nil {
+			allErrors = append(allErrors, field.Invalid(path.Index(i).Child("value"), assertion.Value, err.Error()))
+		}
+	}
+
+	return allErrors
+}
+
 //////////////////////////////////////////////////
 
 func (r *Ytsaurus) validateInstanceSpec(instanceSpec InstanceSpec, path *field.Path) field.ErrorList {
@@ -468,6 +585,43 @@ func (r *Ytsaurus) validateInstanceSpec(instanceSpec InstanceSpec, path *field.P
 		}
 	}
 
+	if pdb := instanceSpec.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		allErrors = append(allErrors, field.Invalid(path.Child("podDisruptionBudget"), pdb, "minAvailable and maxUnavailable are mutually exclusive"))
+	}
+
+	return allErrors
+}
+
+func (r *Ytsaurus) validateMaintenanceWindows(old *runtime.Object) field.ErrorList {
+	if r.Spec.MaintenanceWindows == nil {
+		return nil
+	}
+	return r.Spec.MaintenanceWindows.validate(field.NewPath("spec").Child("maintenanceWindows"))
+}
+
+func (r *Ytsaurus) validateCoreVersions(old *runtime.Object) field.ErrorList {
+	var allErrors field.ErrorList
+
+	if r.Spec.CoreVersion != "" {
+		if _, err := ParseYtsaurusVersion(r.Spec.CoreVersion); err != nil {
+			allErrors = append(allErrors, field.Invalid(field.NewPath("spec").Child("coreVersion"), r.Spec.CoreVersion, err.Error()))
+		}
+	}
+
+	newVersions := r.Spec.GetCoreVersions()
+	oldVersions := make(map[string]YtsaurusVersion)
+	if old != nil {
+		oldYtsaurus := (*old).(*Ytsaurus)
+		oldVersions = oldYtsaurus.Spec.GetCoreVersions()
+	}
+	// Returning to the versions the cluster is running with is allowed, e.g. on the update rollback.
+	for path, runningVersion := range r.GetRunningCoreVersions() {
+		if newVersion, ok := newVersions[path]; ok && newVersion == runningVersion {
+			delete(oldVersions, path)
+		}
+	}
+	allErrors = append(allErrors, ValidateCoreVersions(oldVersions, newVersions)...)
+
 	return allErrors
 }
 
@@ -492,6 +646,11 @@ func (r *Ytsaurus) validateYtsaurus(old *runtime.Object) field.ErrorList {
 	allErrors = append(allErrors, r.validateQueueAgents(old)...)
 	allErrors = append(allErrors, r.validateSpyt(old)...)
 	allErrors = append(allErrors, r.validateYQLAgents(old)...)
+	allErrors = append(allErrors, r.validateUpdateApprovalStates(old)...)
+	allErrors = append(allErrors, r.validateUpdateStateTimeouts(old)...)
+	allErrors = append(allErrors, r.validateUpdatePossibilityChecks(old)...)
+	allErrors = append(allErrors, r.validateCoreVersions(old)...)
+	allErrors = append(allErrors, r.validateMaintenanceWindows(old)...)
 
 	return allErrors
 }
@@ -526,6 +685,11 @@ func (r *Ytsaurus) ValidateUpdate(old runtime.Object) error {
 func (r *Ytsaurus) ValidateDelete() error {
 	ytsauruslog.Info("validate delete", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object deletion.
+	if r.Spec.DeletionProtection {
+		return apierrors.NewForbidden(
+			schema.GroupResource{Group: "cluster.ytsaurus.tech", Resource: "ytsaurus"},
+			r.Name,
+			fmt.Errorf("deletion protection is enabled, disable spec.deletionProtection to delete the cluster"))
+	}
 	return nil
 }
@@ -1,12 +1,18 @@
 package v1
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ptr "k8s.io/utils/pointer"
 )
 
 var _ = Describe("Test for Ytsaurus webhooks", func() {
@@ -316,5 +322,117 @@ var _ = Describe("Test for Ytsaurus webhooks", func() {
 			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.primaryMasters.hostAddresses: Invalid value")))
 		})
 
+		It("Should not accept rolling update strategy for masters", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.PrimaryMasters.UpdateStrategy = ComponentUpdateStrategyRollingUpdate
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.primaryMasters.updateStrategy: Unsupported value")))
+		})
+
+		It("Should not accept rolling update strategy for data and tablet nodes", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.DataNodes[0].UpdateStrategy = ComponentUpdateStrategyRollingUpdate
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.dataNodes[0].updateStrategy: Unsupported value")))
+
+			ytsaurus = CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.TabletNodes[0].UpdateStrategy = ComponentUpdateStrategyRollingUpdate
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.tabletNodes[0].updateStrategy: Unsupported value")))
+		})
+
+		It("Should not accept unknown update approval states", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.UpdateApprovalStates = []UpdateState{UpdateStateWaitingForSnapshots, "WaitingForNothing"}
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.updateApprovalStates[1]: Unsupported value")))
+		})
+
+		It("Should not accept non-positive update state timeouts", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.UpdateStateTimeouts = []UpdateStateTimeout{
+				{State: UpdateStateWaitingForTabletCellsRemoved, Timeout: metav1.Duration{}},
+			}
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.updateStateTimeouts[0].timeout: Invalid value")))
+		})
+
+		It("Should not accept invalid cypress assertions", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.UpdatePossibilityChecks = &UpdatePossibilityChecksSpec{
+				CypressAssertions: []CypressAssertionSpec{
+					{Name: "SafeMode", Path: "//sys/@enable_safe_mode", Value: "fals"},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.updatePossibilityChecks.cypressAssertions[0].value: Invalid value")))
+		})
+
+		It("Should not accept a core version downgrade", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.CoreImage = CoreImageNextVer
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(Succeed())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      YtsaurusName,
+				Namespace: namespace,
+			}, ytsaurus)).Should(Succeed())
+
+			ytsaurus.Spec.CoreImage = CoreImageFirst
+
+			Expect(k8sClient.Update(ctx, ytsaurus)).Should(MatchError(ContainSubstring("downgrade from version 23.2.0 to 23.1.0 is not supported")))
+		})
+
+		It("Should not accept components with incompatible versions", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.Discovery.Image = ptr.String("ytsaurus/ytsaurus:stable-24.1.0")
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.discovery: Forbidden: version 24.1.0 is incompatible with primary masters version 23.1.0")))
+		})
+
+		It("Should accept the samples with separately versioned components", func() {
+			for _, sample := range []string{"cluster_v1_minikube.yaml", "cluster_v1_demo.yaml"} {
+				file, err := os.Open(filepath.Join("..", "..", "config", "samples", sample))
+				Expect(err).Should(Succeed())
+				defer file.Close()
+
+				ytsaurus := &Ytsaurus{}
+				Expect(yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(ytsaurus)).Should(Succeed())
+				ytsaurus.Namespace = namespace
+
+				Expect(k8sClient.Create(ctx, ytsaurus)).Should(Succeed(), sample)
+			}
+		})
+
+		It("Should not accept invalid core version", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.CoreVersion = "latest"
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.coreVersion: Invalid value")))
+		})
+
+		It("Should not accept invalid maintenance windows", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.MaintenanceWindows = &MaintenanceWindowsSpec{
+				TimeZone: "Europe/Amsterdam",
+				Windows: []MaintenanceWindowSpec{
+					{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+					{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(MatchError(ContainSubstring("spec.maintenanceWindows.windows[1].schedule: Invalid value")))
+		})
+
+		It("Should not delete a cluster with deletion protection", func() {
+			ytsaurus := CreateBaseYtsaurusResource(namespace)
+			ytsaurus.Spec.DeletionProtection = true
+
+			Expect(k8sClient.Create(ctx, ytsaurus)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, ytsaurus)).Should(MatchError(ContainSubstring("deletion protection is enabled")))
+
+			ytsaurus.Spec.DeletionProtection = false
+			Expect(k8sClient.Update(ctx, ytsaurus)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, ytsaurus)).Should(Succeed())
+		})
+
 	})
 })
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -262,6 +263,62 @@ func (in *ClusterNodesSpec) DeepCopy() *ClusterNodesSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentDependencies) DeepCopyInto(out *ComponentDependencies) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlockedBy != nil {
+		in, out := &in.BlockedBy, &out.BlockedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentDependencies.
+func (in *ComponentDependencies) DeepCopy() *ComponentDependencies {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentDependencies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentUpdatePlan) DeepCopyInto(out *ComponentUpdatePlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentUpdatePlan.
+func (in *ComponentUpdatePlan) DeepCopy() *ComponentUpdatePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentUpdatePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerAgentsSpec) DeepCopyInto(out *ControllerAgentsSpec) {
 	*out = *in
@@ -278,6 +335,42 @@ func (in *ControllerAgentsSpec) DeepCopy() *ControllerAgentsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CypressAssertionSpec) DeepCopyInto(out *CypressAssertionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CypressAssertionSpec.
+func (in *CypressAssertionSpec) DeepCopy() *CypressAssertionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CypressAssertionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataNodesScaleDown) DeepCopyInto(out *DataNodesScaleDown) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataNodesScaleDown.
+func (in *DataNodesScaleDown) DeepCopy() *DataNodesScaleDown {
+	if in == nil {
+		return nil
+	}
+	out := new(DataNodesScaleDown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataNodesSpec) DeepCopyInto(out *DataNodesSpec) {
 	*out = *in
@@ -390,6 +483,11 @@ func (in *ExecNodesSpec) DeepCopyInto(out *ExecNodesSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecNodesSpec.
@@ -556,6 +654,30 @@ func (in *InstanceSpec) DeepCopyInto(out *InstanceSpec) {
 		*out = new(RPCTransportSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RestartedAt != nil {
+		in, out := &in.RestartedAt, &out.RestartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceSpec.
@@ -652,6 +774,42 @@ func (in *LogRotationPolicy) DeepCopy() *LogRotationPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowsSpec) DeepCopyInto(out *MaintenanceWindowsSpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MaintenanceWindowSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowsSpec.
+func (in *MaintenanceWindowsSpec) DeepCopy() *MaintenanceWindowsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MasterCachesSpec) DeepCopyInto(out *MasterCachesSpec) {
 	*out = *in
@@ -740,6 +898,31 @@ func (in *OauthUserInfoHandlerSpec) DeepCopy() *OauthUserInfoHandlerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QueryTrackerSpec) DeepCopyInto(out *QueryTrackerSpec) {
 	*out = *in
@@ -1191,6 +1374,145 @@ func (in *UISpec) DeepCopy() *UISpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateHistoryEntry) DeepCopyInto(out *UpdateHistoryEntry) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OldImages != nil {
+		in, out := &in.OldImages, &out.OldImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NewImages != nil {
+		in, out := &in.NewImages, &out.NewImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StateDurations != nil {
+		in, out := &in.StateDurations, &out.StateDurations
+		*out = make([]UpdateStateDuration, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateHistoryEntry.
+func (in *UpdateHistoryEntry) DeepCopy() *UpdateHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePlan) DeepCopyInto(out *UpdatePlan) {
+	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentUpdatePlan, len(*in))
+		copy(*out, *in)
+	}
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make([]UpdateState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePlan.
+func (in *UpdatePlan) DeepCopy() *UpdatePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePossibilityChecksSpec) DeepCopyInto(out *UpdatePossibilityChecksSpec) {
+	*out = *in
+	if in.AllowedDegradedBundles != nil {
+		in, out := &in.AllowedDegradedBundles, &out.AllowedDegradedBundles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxDataMissingChunks != nil {
+		in, out := &in.MaxDataMissingChunks, &out.MaxDataMissingChunks
+		*out = new(int)
+		**out = **in
+	}
+	if in.CypressAssertions != nil {
+		in, out := &in.CypressAssertions, &out.CypressAssertions
+		*out = make([]CypressAssertionSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePossibilityChecksSpec.
+func (in *UpdatePossibilityChecksSpec) DeepCopy() *UpdatePossibilityChecksSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePossibilityChecksSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStateDuration) DeepCopyInto(out *UpdateStateDuration) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStateDuration.
+func (in *UpdateStateDuration) DeepCopy() *UpdateStateDuration {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStateDuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStateTimeout) DeepCopyInto(out *UpdateStateTimeout) {
+	*out = *in
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStateTimeout.
+func (in *UpdateStateTimeout) DeepCopy() *UpdateStateTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStateTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpdateStatus) DeepCopyInto(out *UpdateStatus) {
 	*out = *in
@@ -1216,6 +1538,10 @@ func (in *UpdateStatus) DeepCopyInto(out *UpdateStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StateStartTime != nil {
+		in, out := &in.StateStartTime, &out.StateStartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStatus.
@@ -1336,6 +1662,31 @@ func (in *YtsaurusSpec) DeepCopyInto(out *YtsaurusSpec) {
 		*out = new(RPCTransportSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpdateApprovalStates != nil {
+		in, out := &in.UpdateApprovalStates, &out.UpdateApprovalStates
+		*out = make([]UpdateState, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdateStateTimeouts != nil {
+		in, out := &in.UpdateStateTimeouts, &out.UpdateStateTimeouts
+		*out = make([]UpdateStateTimeout, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdatePossibilityChecks != nil {
+		in, out := &in.UpdatePossibilityChecks, &out.UpdatePossibilityChecks
+		*out = new(UpdatePossibilityChecksSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = new(MaintenanceWindowsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheckPeriod != nil {
+		in, out := &in.HealthCheckPeriod, &out.HealthCheckPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	out.RackAwareness = in.RackAwareness
 	if in.ExtraPodAnnotations != nil {
 		in, out := &in.ExtraPodAnnotations, &out.ExtraPodAnnotations
@@ -1481,6 +1832,57 @@ func (in *YtsaurusStatus) DeepCopyInto(out *YtsaurusStatus) {
 		}
 	}
 	in.UpdateStatus.DeepCopyInto(&out.UpdateStatus)
+	if in.RunningImages != nil {
+		in, out := &in.RunningImages, &out.RunningImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RunningDataNodesInstanceCounts != nil {
+		in, out := &in.RunningDataNodesInstanceCounts, &out.RunningDataNodesInstanceCounts
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UpdateHistory != nil {
+		in, out := &in.UpdateHistory, &out.UpdateHistory
+		*out = make([]UpdateHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdatePlan != nil {
+		in, out := &in.UpdatePlan, &out.UpdatePlan
+		*out = new(UpdatePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataNodesScaleDowns != nil {
+		in, out := &in.DataNodesScaleDowns, &out.DataNodesScaleDowns
+		*out = make([]DataNodesScaleDown, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComponentDependencies != nil {
+		in, out := &in.ComponentDependencies, &out.ComponentDependencies
+		*out = make([]ComponentDependencies, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastHealthCheckTime != nil {
+		in, out := &in.LastHealthCheckTime, &out.LastHealthCheckTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YtsaurusStatus.
@@ -1492,3 +1894,18 @@ func (in *YtsaurusStatus) DeepCopy() *YtsaurusStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YtsaurusVersion) DeepCopyInto(out *YtsaurusVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YtsaurusVersion.
+func (in *YtsaurusVersion) DeepCopy() *YtsaurusVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(YtsaurusVersion)
+	in.DeepCopyInto(out)
+	return out
+}
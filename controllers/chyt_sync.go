@@ -30,7 +30,11 @@ func (r *ChytReconciler) Sync(ctx context.Context, resource *ytv1.Chyt, ytsaurus
 		return ctrl.Result{}, nil
 	}
 
-	status := component.Status(ctx)
+	status, err := component.Status(ctx)
+	if err != nil {
+		logger.Error(err, "failed to evaluate CHYT status")
+		return ctrl.Result{Requeue: true}, err
+	}
 	if status.SyncStatus == components.SyncStatusBlocked {
 		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
 	}
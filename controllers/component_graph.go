@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/utils/strings/slices"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+// componentGraph keeps the components and the components they wait for.
+type componentGraph struct {
+	components   []components.Component
+	dependencies map[string][]components.Component
+	// instanceGroupLabels are the labels of the named instance groups keyed by the label of their kind.
+	instanceGroupLabels map[string][]string
+}
+
+func newComponentGraph() *componentGraph {
+	return &componentGraph{
+		dependencies:        make(map[string][]components.Component),
+		instanceGroupLabels: make(map[string][]string),
+	}
+}
+
+// addInstanceGroups makes the label of the kind match the labels of its instance groups,
+// e.g. the dependency on tablet nodes matches the tablet nodes of all groups.
+func (g *componentGraph) addInstanceGroups(label string, groups []components.Component) {
+	for _, group := range groups {
+		if group.GetLabel() != label {
+			g.instanceGroupLabels[label] = append(g.instanceGroupLabels[label], group.GetLabel())
+		}
+	}
+}
+
+// add registers the component, nil dependencies are ignored since they refer to disabled components.
+func (g *componentGraph) add(component components.Component, dependencies ...components.Component) {
+	g.components = append(g.components, component)
+	for _, dependency := range dependencies {
+		if dependency != nil {
+			g.dependencies[component.GetName()] = append(g.dependencies[component.GetName()], dependency)
+		}
+	}
+}
+
+func (g *componentGraph) getDependencies(component components.Component) []components.Component {
+	return g.dependencies[component.GetName()]
+}
+
+// sort returns the components ordered so that dependencies go before the components depending on them.
+// Otherwise, the order of registration is kept.
+func (g *componentGraph) sort() ([]components.Component, error) {
+	registered := make(map[string]bool)
+	for _, cmp := range g.components {
+		if registered[cmp.GetName()] {
+			return nil, fmt.Errorf("component %s is registered twice", cmp.GetName())
+		}
+		registered[cmp.GetName()] = true
+	}
+
+	sorted := make([]components.Component, 0, len(g.components))
+	added := make(map[string]bool)
+	var path []string
+
+	var visit func(cmp components.Component) error
+	visit = func(cmp components.Component) error {
+		name := cmp.GetName()
+		if added[name] {
+			return nil
+		}
+		for i, visiting := range path {
+			if visiting == name {
+				return fmt.Errorf("components have cyclic dependencies: %s", strings.Join(append(path[i:], name), " -> "))
+			}
+		}
+		if !registered[name] {
+			return fmt.Errorf("component %s depends on unregistered component %s", path[len(path)-1], name)
+		}
+
+		path = append(path, name)
+		for _, dependency := range g.getDependencies(cmp) {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		sorted = append(sorted, cmp)
+		added[name] = true
+		return nil
+	}
+
+	for _, cmp := range g.components {
+		if err := visit(cmp); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// GetBlockingDependency implements components.DependencyChecker, the component waits only for the dependencies
+// it was registered with. The status of the dependency is evaluated again since it could be synced in the meantime.
+func (g *componentGraph) GetBlockingDependency(ctx context.Context, component string, labels ...string) string {
+	for _, dependency := range g.dependencies[component] {
+		if !g.hasAnyComponentLabel(dependency, labels) {
+			continue
+		}
+		status, err := dependency.Status(ctx)
+		if err != nil || !isDependencyRunning(dependency, status.SyncStatus) {
+			return dependency.GetName()
+		}
+	}
+	return ""
+}
+
+func (g *componentGraph) hasAnyComponentLabel(component components.Component, labels []string) bool {
+	if len(labels) == 0 {
+		return true
+	}
+	for _, label := range labels {
+		if component.GetLabel() == label || slices.Contains(g.instanceGroupLabels[label], component.GetLabel()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDependencyRunning checks that the dependency doesn't block the component,
+// the client to the cluster is used right away, so it should be ready.
+func isDependencyRunning(dependency components.Component, syncStatus components.SyncStatus) bool {
+	if dependency.GetLabel() == consts.YTComponentLabelClient {
+		return syncStatus == components.SyncStatusReady
+	}
+	return components.IsRunningStatus(syncStatus)
+}
+
+// getBlockingDependencies returns the dependencies of the blocked component which are not running.
+func (g *componentGraph) getBlockingDependencies(
+	component components.Component,
+	statuses map[string]components.ComponentStatus,
+) []string {
+	if statuses[component.GetName()].SyncStatus != components.SyncStatusBlocked {
+		return nil
+	}
+
+	var blocking []string
+	for _, dependency := range g.getDependencies(component) {
+		if !isDependencyRunning(dependency, statuses[dependency.GetName()].SyncStatus) {
+			blocking = append(blocking, dependency.GetName())
+		}
+	}
+	return blocking
+}
+
+// buildStatus describes the dependencies of the components for the resource status.
+func (g *componentGraph) buildStatus(
+	sorted []components.Component,
+	statuses map[string]components.ComponentStatus,
+) []ytv1.ComponentDependencies {
+	result := make([]ytv1.ComponentDependencies, 0, len(sorted))
+	for _, cmp := range sorted {
+		dependencies := ytv1.ComponentDependencies{
+			Name:      cmp.GetName(),
+			BlockedBy: g.getBlockingDependencies(cmp, statuses),
+		}
+		for _, dependency := range g.getDependencies(cmp) {
+			dependencies.DependsOn = append(dependencies.DependsOn, dependency.GetName())
+		}
+		result = append(result, dependencies)
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+type fakeComponent struct {
+	name  string
+	label string
+	// syncStatus is Ready if it isn't set.
+	syncStatus components.SyncStatus
+}
+
+func (c *fakeComponent) Fetch(ctx context.Context) error { return nil }
+func (c *fakeComponent) Sync(ctx context.Context) error  { return nil }
+func (c *fakeComponent) Status(ctx context.Context) (components.ComponentStatus, error) {
+	if c.syncStatus == "" {
+		return components.SimpleStatus(components.SyncStatusReady), nil
+	}
+	return components.SimpleStatus(c.syncStatus), nil
+}
+func (c *fakeComponent) GetName() string { return c.name }
+func (c *fakeComponent) GetLabel() string {
+	if c.label == "" {
+		return c.name
+	}
+	return c.label
+}
+func (c *fakeComponent) SetReadyCondition(status components.ComponentStatus) {}
+func (c *fakeComponent) IsUpdatable() bool                                   { return true }
+
+func getNames(cmps []components.Component) []string {
+	names := make([]string, 0, len(cmps))
+	for _, cmp := range cmps {
+		names = append(names, cmp.GetName())
+	}
+	return names
+}
+
+func TestComponentGraphSort(t *testing.T) {
+	master := &fakeComponent{name: "Master"}
+	client := &fakeComponent{name: "YtsaurusClient"}
+	proxy := &fakeComponent{name: "HttpProxy"}
+	node := &fakeComponent{name: "DataNode"}
+	discovery := &fakeComponent{name: "Discovery"}
+
+	graph := newComponentGraph()
+	graph.add(client, proxy)
+	graph.add(master)
+	graph.add(node, master, client)
+	graph.add(proxy, master, nil)
+	graph.add(discovery)
+
+	sorted, err := graph.sort()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Master", "HttpProxy", "YtsaurusClient", "DataNode", "Discovery"}, getNames(sorted))
+}
+
+func TestComponentGraphCycle(t *testing.T) {
+	master := &fakeComponent{name: "Master"}
+	client := &fakeComponent{name: "YtsaurusClient"}
+	proxy := &fakeComponent{name: "HttpProxy"}
+
+	graph := newComponentGraph()
+	graph.add(master)
+	graph.add(client, proxy)
+	graph.add(proxy, client)
+
+	_, err := graph.sort()
+	require.ErrorContains(t, err, "YtsaurusClient -> HttpProxy -> YtsaurusClient")
+
+	graph = newComponentGraph()
+	graph.add(client, proxy)
+	_, err = graph.sort()
+	require.ErrorContains(t, err, "unregistered component HttpProxy")
+}
+
+func TestComponentGraphStatus(t *testing.T) {
+	master := &fakeComponent{name: "Master"}
+	proxy := &fakeComponent{name: "HttpProxy"}
+	discovery := &fakeComponent{name: "Discovery"}
+
+	graph := newComponentGraph()
+	graph.add(master)
+	graph.add(discovery)
+	graph.add(proxy, master, discovery)
+
+	sorted, err := graph.sort()
+	require.NoError(t, err)
+
+	statuses := map[string]components.ComponentStatus{
+		"Master":    components.SimpleStatus(components.SyncStatusPending),
+		"Discovery": components.SimpleStatus(components.SyncStatusReady),
+		"HttpProxy": components.WaitingStatus(components.SyncStatusBlocked, "Master"),
+	}
+	require.Equal(t, []ytv1.ComponentDependencies{
+		{Name: "Master"},
+		{Name: "Discovery"},
+		{Name: "HttpProxy", DependsOn: []string{"Master", "Discovery"}, BlockedBy: []string{"Master"}},
+	}, graph.buildStatus(sorted, statuses))
+}
+
+func TestComponentGraphBlockingDependency(t *testing.T) {
+	ctx := context.Background()
+	master := &fakeComponent{name: "Master", label: consts.YTComponentLabelMaster}
+	client := &fakeComponent{name: "YtsaurusClient", label: consts.YTComponentLabelClient}
+	tabletNode := &fakeComponent{name: "TabletNode", label: consts.YTComponentLabelTabletNode}
+	namedTabletNode := &fakeComponent{name: "TabletNodeSsd", label: consts.YTComponentLabelTabletNode + "-ssd"}
+	scheduler := &fakeComponent{name: "Scheduler", label: consts.YTComponentLabelScheduler}
+
+	graph := newComponentGraph()
+	graph.add(scheduler, master, tabletNode, namedTabletNode)
+	graph.addInstanceGroups(consts.YTComponentLabelTabletNode, []components.Component{tabletNode, namedTabletNode})
+
+	require.Empty(t, graph.GetBlockingDependency(ctx, "Scheduler"))
+
+	namedTabletNode.syncStatus = components.SyncStatusPending
+	require.Equal(t, "TabletNodeSsd", graph.GetBlockingDependency(ctx, "Scheduler"))
+	require.Equal(t, "TabletNodeSsd", graph.GetBlockingDependency(ctx, "Scheduler", consts.YTComponentLabelTabletNode))
+	require.Empty(t, graph.GetBlockingDependency(ctx, "Scheduler", consts.YTComponentLabelMaster))
+
+	// Only the declared dependencies are checked.
+	client.syncStatus = components.SyncStatusBlocked
+	require.Empty(t, graph.GetBlockingDependency(ctx, "Scheduler", consts.YTComponentLabelClient))
+
+	master.syncStatus = components.SyncStatusNeedFullUpdate
+	require.Empty(t, graph.GetBlockingDependency(ctx, "Scheduler", consts.YTComponentLabelMaster))
+	master.syncStatus = components.SyncStatusUpdating
+	require.Equal(t, "Master", graph.GetBlockingDependency(ctx, "Scheduler"))
+}
+
+func TestComponentGraphDependencyLabels(t *testing.T) {
+	ctx := context.Background()
+	master := &fakeComponent{name: "Master", label: consts.YTComponentLabelMaster}
+	masterCache := &fakeComponent{name: "MasterCache", label: consts.YTComponentLabelMasterCache}
+	client := &fakeComponent{name: "YtsaurusClient", label: consts.YTComponentLabelClient}
+	queueAgent := &fakeComponent{name: "QueueAgent", label: "yt-queue-agent"}
+
+	graph := newComponentGraph()
+	graph.add(queueAgent, master, masterCache, client)
+
+	// Label of masters doesn't match master caches.
+	masterCache.syncStatus = components.SyncStatusPending
+	require.Empty(t, graph.GetBlockingDependency(ctx, "QueueAgent", consts.YTComponentLabelMaster))
+	require.Equal(t, "MasterCache", graph.GetBlockingDependency(ctx, "QueueAgent", consts.YTComponentLabelMasterCache))
+
+	// Client to the cluster is used right away, so it should be ready.
+	client.syncStatus = components.SyncStatusNeedFullUpdate
+	require.Equal(t, "YtsaurusClient", graph.GetBlockingDependency(ctx, "QueueAgent", consts.YTComponentLabelClient))
+	client.syncStatus = components.SyncStatusReady
+	require.Empty(t, graph.GetBlockingDependency(ctx, "QueueAgent", consts.YTComponentLabelClient))
+}
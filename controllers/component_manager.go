@@ -4,12 +4,17 @@ import (
 	"context"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
 	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
 )
 
@@ -18,7 +23,9 @@ type ComponentManager struct {
 	allComponents         []components.Component
 	queryTrackerComponent components.Component
 	schedulerComponent    components.Component
+	ytsaurusClient        components.YtsaurusClient
 	status                ComponentManagerStatus
+	componentStatuses     map[string]components.ComponentStatus
 }
 
 type ComponentManagerStatus struct {
@@ -27,6 +34,11 @@ type ComponentManagerStatus struct {
 	needFullUpdate     bool
 	needLocalUpdate    []components.Component
 	allReadyOrUpdating bool
+	// degradedComponents are the components which status can't be evaluated.
+	degradedComponents []string
+	degradedBackoff    time.Duration
+	// recovered is set when the cluster is not degraded anymore.
+	recovered bool
 }
 
 func NewComponentManager(
@@ -38,6 +50,9 @@ func NewComponentManager(
 
 	cfgen := ytconfig.NewGenerator(resource, getClusterDomain(ytsaurus.APIProxy().Client()))
 
+	// The dependencies are declared only here, the components ask the graph whether they are running.
+	graph := newComponentGraph()
+
 	d := components.NewDiscovery(cfgen, ytsaurus)
 	m := components.NewMaster(cfgen, ytsaurus)
 	mc := components.NewMasterCache(cfgen, ytsaurus)
@@ -47,97 +62,126 @@ func NewComponentManager(
 	}
 	var hps []components.Component
 	for _, hpSpec := range ytsaurus.GetResource().Spec.HTTPProxies {
-		hps = append(hps, components.NewHTTPProxy(cfgen, ytsaurus, m, hpSpec))
+		hps = append(hps, components.NewHTTPProxy(cfgen, ytsaurus, graph, hpSpec))
 	}
-	yc := components.NewYtsaurusClient(cfgen, ytsaurus, hps[0])
+	yc := components.NewYtsaurusClient(cfgen, ytsaurus, graph)
 
 	var dnds []components.Component
 	if resource.Spec.DataNodes != nil && len(resource.Spec.DataNodes) > 0 {
 		for _, dndSpec := range ytsaurus.GetResource().Spec.DataNodes {
-			dnds = append(dnds, components.NewDataNode(cfgen, ytsaurus, yc, m, dndSpec))
+			dnds = append(dnds, components.NewDataNode(cfgen, ytsaurus, yc, graph, dndSpec))
 		}
 	}
 
 	var s components.Component
 
-	allComponents := []components.Component{
-		d, m, yc, mc,
+	graph.add(d)
+	graph.add(m)
+	graph.add(yc, hps[0])
+	graph.add(mc)
+	for _, sm := range secondaryMasters {
+		graph.add(sm)
+	}
+	for _, dnd := range dnds {
+		graph.add(dnd, m, yc)
 	}
-	allComponents = append(allComponents, secondaryMasters...)
-	allComponents = append(allComponents, dnds...)
-	allComponents = append(allComponents, hps...)
+	graph.addInstanceGroups(consts.YTComponentLabelDataNode, dnds)
+	for _, hp := range hps {
+		graph.add(hp, m)
+	}
+	graph.addInstanceGroups(consts.YTComponentLabelHTTPProxy, hps)
 
 	if resource.Spec.UI != nil {
-		ui := components.NewUI(cfgen, ytsaurus, m)
-		allComponents = append(allComponents, ui)
+		ui := components.NewUI(cfgen, ytsaurus, graph)
+		graph.add(ui, m)
 	}
 
 	if resource.Spec.RPCProxies != nil && len(resource.Spec.RPCProxies) > 0 {
 		var rps []components.Component
 		for _, rpSpec := range ytsaurus.GetResource().Spec.RPCProxies {
-			rps = append(rps, components.NewRPCProxy(cfgen, ytsaurus, m, rpSpec))
+			rps = append(rps, components.NewRPCProxy(cfgen, ytsaurus, graph, rpSpec))
+		}
+		for _, rp := range rps {
+			graph.add(rp, m)
 		}
-		allComponents = append(allComponents, rps...)
+		graph.addInstanceGroups(consts.YTComponentLabelRPCProxy, rps)
 	}
 
 	if resource.Spec.TCPProxies != nil && len(resource.Spec.TCPProxies) > 0 {
 		var tps []components.Component
 		for _, tpSpec := range ytsaurus.GetResource().Spec.TCPProxies {
-			tps = append(tps, components.NewTCPProxy(cfgen, ytsaurus, m, tpSpec))
+			tps = append(tps, components.NewTCPProxy(cfgen, ytsaurus, graph, tpSpec))
 		}
-		allComponents = append(allComponents, tps...)
+		for _, tp := range tps {
+			graph.add(tp, m)
+		}
+		graph.addInstanceGroups(consts.YTComponentLabelTCPProxy, tps)
 	}
 
 	var ends []components.Component
 	if resource.Spec.ExecNodes != nil && len(resource.Spec.ExecNodes) > 0 {
 		for _, endSpec := range ytsaurus.GetResource().Spec.ExecNodes {
-			ends = append(ends, components.NewExecNode(cfgen, ytsaurus, yc, m, endSpec))
+			ends = append(ends, components.NewExecNode(cfgen, ytsaurus, yc, graph, endSpec))
 		}
 	}
-	allComponents = append(allComponents, ends...)
+	for _, end := range ends {
+		graph.add(end, m, yc)
+	}
+	graph.addInstanceGroups(consts.YTComponentLabelExecNode, ends)
 
 	var tnds []components.Component
 	if resource.Spec.TabletNodes != nil && len(resource.Spec.TabletNodes) > 0 {
 		for idx, tndSpec := range ytsaurus.GetResource().Spec.TabletNodes {
-			tnds = append(tnds, components.NewTabletNode(cfgen, ytsaurus, yc, tndSpec, idx == 0))
+			tnds = append(tnds, components.NewTabletNode(cfgen, ytsaurus, yc, graph, tndSpec, idx == 0))
 		}
 	}
-	allComponents = append(allComponents, tnds...)
+	for _, tnd := range tnds {
+		graph.add(tnd, yc)
+	}
+	graph.addInstanceGroups(consts.YTComponentLabelTabletNode, tnds)
 
 	if resource.Spec.Schedulers != nil {
-		s = components.NewScheduler(cfgen, ytsaurus, m, ends, tnds)
-		allComponents = append(allComponents, s)
+		s = components.NewScheduler(cfgen, ytsaurus, graph)
+		graph.add(s, append(append([]components.Component{m}, ends...), tnds...)...)
 	}
 
 	if resource.Spec.ControllerAgents != nil {
-		ca := components.NewControllerAgent(cfgen, ytsaurus, m)
-		allComponents = append(allComponents, ca)
+		ca := components.NewControllerAgent(cfgen, ytsaurus, graph)
+		graph.add(ca, m)
 	}
 
 	var q components.Component
 	if resource.Spec.QueryTrackers != nil && resource.Spec.Schedulers != nil && resource.Spec.TabletNodes != nil && len(resource.Spec.TabletNodes) > 0 {
-		q = components.NewQueryTracker(cfgen, ytsaurus, yc, tnds)
-		allComponents = append(allComponents, q)
+		q = components.NewQueryTracker(cfgen, ytsaurus, yc, graph)
+		graph.add(q, append([]components.Component{yc}, tnds...)...)
 	}
 
 	if resource.Spec.QueueAgents != nil && resource.Spec.TabletNodes != nil && len(resource.Spec.TabletNodes) > 0 {
-		qa := components.NewQueueAgent(cfgen, ytsaurus, yc, m, tnds)
-		allComponents = append(allComponents, qa)
+		qa := components.NewQueueAgent(cfgen, ytsaurus, yc, graph)
+		graph.add(qa, append([]components.Component{m, yc}, tnds...)...)
 	}
 
 	if resource.Spec.YQLAgents != nil {
-		yqla := components.NewYQLAgent(cfgen, ytsaurus, m)
-		allComponents = append(allComponents, yqla)
+		yqla := components.NewYQLAgent(cfgen, ytsaurus, graph)
+		graph.add(yqla, m)
 	}
 
 	if (resource.Spec.DeprecatedChytController != nil || resource.Spec.StrawberryController != nil) && resource.Spec.Schedulers != nil {
-		strawberry := components.NewStrawberryController(cfgen, ytsaurus, m, s, dnds)
-		allComponents = append(allComponents, strawberry)
+		strawberry := components.NewStrawberryController(cfgen, ytsaurus, graph)
+		graph.add(strawberry, append([]components.Component{m, s}, dnds...)...)
+	}
+
+	// Components are fetched and synced after their dependencies.
+	allComponents, err := graph.sort()
+	if err != nil {
+		return nil, err
 	}
 
 	// Fetch component status.
 	var readyComponents []string
 	var notReadyComponents []string
+	var unmanagedComponents []string
+	componentStatuses := make(map[string]components.ComponentStatus)
 
 	status := ComponentManagerStatus{
 		needInit:           false,
@@ -153,9 +197,16 @@ func NewComponentManager(
 			return nil, err
 		}
 
-		componentStatus := c.Status(ctx)
+		componentStatus, err := c.Status(ctx)
+		if err != nil {
+			logger.Error(err, "failed to evaluate component status", "component", c.GetName())
+			componentStatus = components.DegradedStatus(err)
+			status.degradedComponents = append(status.degradedComponents, c.GetName())
+		}
 		c.SetReadyCondition(componentStatus)
+		componentStatuses[c.GetName()] = componentStatus
 		syncStatus := componentStatus.SyncStatus
+		metrics.ReportComponentSyncStatus(resource, c.GetName(), string(syncStatus))
 
 		if syncStatus == components.SyncStatusNeedFullUpdate {
 			status.needFullUpdate = true
@@ -168,16 +219,25 @@ func NewComponentManager(
 			status.needLocalUpdate = append(status.needLocalUpdate, c)
 		}
 
-		if !components.IsRunningStatus(syncStatus) {
+		// It is unknown whether the degraded component needs initialization, so it is just retried.
+		if !components.IsRunningStatus(syncStatus) && syncStatus != components.SyncStatusDegraded {
 			status.needInit = true
 		}
 
+		if syncStatus == components.SyncStatusUnmanaged {
+			// The component is taken over by hand, so it neither needs sync nor blocks the update.
+			unmanagedComponents = append(unmanagedComponents, c.GetName())
+			continue
+		}
+
 		if syncStatus != components.SyncStatusReady && syncStatus != components.SyncStatusUpdating {
 			status.allReadyOrUpdating = false
 		}
 
 		if syncStatus != components.SyncStatusReady {
-			logger.Info("component is not ready", "component", c.GetName(), "syncStatus", syncStatus)
+			// Dependencies go first, so their statuses are already known.
+			logger.Info("component is not ready", "component", c.GetName(), "syncStatus", syncStatus,
+				"blockedBy", graph.getBlockingDependencies(c, componentStatuses))
 			notReadyComponents = append(notReadyComponents, c.GetName())
 			status.needSync = true
 		} else {
@@ -185,9 +245,21 @@ func NewComponentManager(
 		}
 	}
 
+	resource.Status.ComponentDependencies = graph.buildStatus(allComponents, componentStatuses)
+	now := metav1.Now()
+	resource.Status.Components = buildComponentStatuses(allComponents, componentStatuses, resource.Status.Components, now)
+	degradedCondition := getDegradedCondition(
+		status.degradedComponents,
+		meta.FindStatusCondition(resource.Status.Conditions, consts.ConditionHealthy))
+	status.recovered = ytsaurus.IsStatusConditionTrue(consts.ConditionDegraded) && degradedCondition.Status == metav1.ConditionFalse
+	ytsaurus.SetStatusCondition(degradedCondition)
+	status.degradedBackoff = getDegradedBackoff(resource.Status.Components, now.Time)
+
 	logger.Info("Ytsaurus sync status",
 		"notReadyComponents", notReadyComponents,
 		"readyComponents", readyComponents,
+		"unmanagedComponents", unmanagedComponents,
+		"degradedComponents", status.degradedComponents,
 		"updateState", resource.Status.UpdateStatus.State,
 		"clusterState", resource.Status.State)
 
@@ -196,7 +268,9 @@ func NewComponentManager(
 		allComponents:         allComponents,
 		queryTrackerComponent: q,
 		schedulerComponent:    s,
+		ytsaurusClient:        yc,
 		status:                status,
+		componentStatuses:     componentStatuses,
 	}, nil
 }
 
@@ -205,7 +279,12 @@ func (cm *ComponentManager) Sync(ctx context.Context) (ctrl.Result, error) {
 
 	hasPending := false
 	for _, c := range cm.allComponents {
-		status := c.Status(ctx)
+		status, err := c.Status(ctx)
+		if err != nil {
+			// The component is degraded, the healthy ones are still synced.
+			logger.Error(err, "failed to evaluate component status", "component", c.GetName())
+			continue
+		}
 
 		if status.SyncStatus == components.SyncStatusPending ||
 			status.SyncStatus == components.SyncStatusUpdating {
@@ -213,6 +292,7 @@ func (cm *ComponentManager) Sync(ctx context.Context) (ctrl.Result, error) {
 			logger.Info("component sync", "component", c.GetName())
 			if err := c.Sync(ctx); err != nil {
 				logger.Error(err, "component sync failed", "component", c.GetName())
+				metrics.ReportComponentSyncError(cm.ytsaurus.GetResource(), c.GetName())
 				return ctrl.Result{Requeue: true}, err
 			}
 		}
@@ -223,6 +303,13 @@ func (cm *ComponentManager) Sync(ctx context.Context) (ctrl.Result, error) {
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	if !hasPending && len(cm.status.degradedComponents) != 0 {
+		logger.Info("some components are degraded, retry later",
+			"components", cm.status.degradedComponents,
+			"backoff", cm.status.degradedBackoff)
+		return ctrl.Result{RequeueAfter: cm.status.degradedBackoff}, nil
+	}
+
 	if !hasPending {
 		// All components are blocked.
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
@@ -235,6 +322,10 @@ func (cm *ComponentManager) needSync() bool {
 	return cm.status.needSync
 }
 
+func (cm *ComponentManager) recovered() bool {
+	return cm.status.recovered
+}
+
 func (cm *ComponentManager) needInit() bool {
 	return cm.status.needInit
 }
@@ -251,12 +342,30 @@ func (cm *ComponentManager) allReadyOrUpdating() bool {
 	return cm.status.allReadyOrUpdating
 }
 
-func (cm *ComponentManager) needQueryTrackerUpdate() bool {
-	return cm.queryTrackerComponent != nil && components.IsUpdatingComponent(cm.ytsaurus, cm.queryTrackerComponent)
+func (cm *ComponentManager) getComponentStatus(component components.Component) components.ComponentStatus {
+	return cm.componentStatuses[component.GetName()]
+}
+
+// getUpdateReasons returns the reasons of the pending update keyed by the component name.
+func (cm *ComponentManager) getUpdateReasons() map[string]string {
+	reasons := make(map[string]string)
+	for name, status := range cm.componentStatuses {
+		if status.SyncStatus == components.SyncStatusNeedFullUpdate || status.SyncStatus == components.SyncStatusNeedLocalUpdate {
+			reasons[name] = status.Message
+		}
+	}
+	return reasons
 }
 
-func (cm *ComponentManager) needSchedulerUpdate() bool {
-	return cm.schedulerComponent != nil && components.IsUpdatingComponent(cm.ytsaurus, cm.schedulerComponent)
+// getUpdateSteps returns the update steps contributed by the components.
+func (cm *ComponentManager) getUpdateSteps() []components.UpdateStep {
+	var steps []components.UpdateStep
+	for _, cmp := range cm.allComponents {
+		if provider, ok := cmp.(components.UpdateStepsProvider); ok {
+			steps = append(steps, provider.GetUpdateSteps()...)
+		}
+	}
+	return steps
 }
 
 func (cm *ComponentManager) arePodsRemoved() bool {
@@ -270,5 +379,11 @@ func (cm *ComponentManager) arePodsRemoved() bool {
 }
 
 func (cm *ComponentManager) areComponentPodsRemoved(component components.Component) bool {
-	return cm.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetPodsRemovedCondition(component.GetName()))
+	if cm.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetPodsRemovedCondition(component.GetName())) {
+		return true
+	}
+	// Components with rolling update strategy replace their pods one by one instead of removal,
+	// but hibernation has to remove the pods of all components.
+	return cm.ytsaurus.GetClusterState() != ytv1.ClusterStateHibernating &&
+		cm.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetPodsUpdatedCondition(component.GetName()))
 }
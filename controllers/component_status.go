@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+const (
+	minDegradedBackoff = 10 * time.Second
+	maxDegradedBackoff = 5 * time.Minute
+)
+
+// getComponentKind returns the name of the component without the name of its group or role.
+func getComponentKind(name string) string {
+	return strings.SplitN(name, "-", 2)[0]
+}
+
+// buildComponentStatuses describes the components for the resource status,
+// the transition time is kept while the sync status of the component stays the same.
+func buildComponentStatuses(
+	cmps []components.Component,
+	statuses map[string]components.ComponentStatus,
+	previous []ytv1.ComponentStatus,
+	now metav1.Time,
+) []ytv1.ComponentStatus {
+	previousByName := make(map[string]ytv1.ComponentStatus)
+	for _, status := range previous {
+		previousByName[status.Name] = status
+	}
+
+	result := make([]ytv1.ComponentStatus, 0, len(cmps))
+	for _, cmp := range cmps {
+		componentStatus := statuses[cmp.GetName()]
+		status := ytv1.ComponentStatus{
+			Name:               cmp.GetName(),
+			Kind:               getComponentKind(cmp.GetName()),
+			SyncStatus:         string(componentStatus.SyncStatus),
+			Message:            componentStatus.Message,
+			LastTransitionTime: now,
+		}
+		if prev, ok := previousByName[status.Name]; ok && prev.SyncStatus == status.SyncStatus {
+			status.LastTransitionTime = prev.LastTransitionTime
+		}
+
+		if provider, ok := cmp.(components.PodsStatusProvider); ok {
+			podsStatus := provider.GetPodsStatus()
+			status.DesiredImage = podsStatus.DesiredImage
+			status.ObservedImage = podsStatus.ObservedImage
+			status.DesiredReplicas = podsStatus.DesiredReplicas
+			status.ReadyReplicas = podsStatus.ReadyReplicas
+			status.UpdatedReplicas = podsStatus.UpdatedReplicas
+			status.ConfigHash = podsStatus.ConfigHash
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// getDegradedCondition reports the components which status can't be evaluated and the failed health checks.
+func getDegradedCondition(degradedComponents []string, healthy *metav1.Condition) metav1.Condition {
+	var reason string
+	var messages []string
+	if healthy != nil && healthy.Status == metav1.ConditionFalse {
+		reason = "HealthCheckFailed"
+		messages = append(messages, healthy.Message)
+	}
+	if len(degradedComponents) != 0 {
+		reason = "StatusEvaluationFailed"
+		messages = append(messages,
+			fmt.Sprintf("Failed to evaluate status of components: %s", strings.Join(degradedComponents, ", ")))
+	}
+
+	if len(messages) == 0 {
+		return metav1.Condition{
+			Type:    consts.ConditionDegraded,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotDegraded",
+			Message: "Statuses of all components are evaluated and the cluster is healthy",
+		}
+	}
+	return metav1.Condition{
+		Type:    consts.ConditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: strings.Join(messages, "; "),
+	}
+}
+
+// getDegradedBackoff returns the delay before the degraded components are evaluated again.
+// The delay equals to the time the components have been degraded for, so the retries get exponentially rarer.
+func getDegradedBackoff(statuses []ytv1.ComponentStatus, now time.Time) time.Duration {
+	backoff := maxDegradedBackoff
+	for _, status := range statuses {
+		if status.SyncStatus != string(components.SyncStatusDegraded) {
+			continue
+		}
+		if degradedFor := now.Sub(status.LastTransitionTime.Time); degradedFor < backoff {
+			backoff = degradedFor
+		}
+	}
+	if backoff < minDegradedBackoff {
+		return minDegradedBackoff
+	}
+	return backoff
+}
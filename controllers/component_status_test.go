@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+)
+
+type fakePodsComponent struct {
+	fakeComponent
+	podsStatus components.PodsStatus
+}
+
+func (c *fakePodsComponent) GetPodsStatus() components.PodsStatus {
+	return c.podsStatus
+}
+
+func TestBuildComponentStatuses(t *testing.T) {
+	before := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	master := &fakePodsComponent{
+		fakeComponent: fakeComponent{name: "Master"},
+		podsStatus: components.PodsStatus{
+			DesiredImage:    "ytsaurus/ytsaurus:23.2",
+			ObservedImage:   "ytsaurus/ytsaurus:23.1",
+			DesiredReplicas: 3,
+			ReadyReplicas:   3,
+			UpdatedReplicas: 1,
+			ConfigHash:      "hash",
+		},
+	}
+	dataNode := &fakeComponent{name: "DataNode-ssd"}
+
+	statuses := map[string]components.ComponentStatus{
+		"Master":       components.SimpleStatus(components.SyncStatusUpdating),
+		"DataNode-ssd": components.WaitingStatus(components.SyncStatusBlocked, "Master"),
+	}
+	previous := []ytv1.ComponentStatus{
+		{Name: "Master", SyncStatus: string(components.SyncStatusReady), LastTransitionTime: before},
+		{Name: "DataNode-ssd", SyncStatus: string(components.SyncStatusBlocked), LastTransitionTime: before},
+	}
+
+	require.Equal(t, []ytv1.ComponentStatus{
+		{
+			Name:               "Master",
+			Kind:               "Master",
+			SyncStatus:         "Updating",
+			Message:            "Updating",
+			DesiredImage:       "ytsaurus/ytsaurus:23.2",
+			ObservedImage:      "ytsaurus/ytsaurus:23.1",
+			DesiredReplicas:    3,
+			ReadyReplicas:      3,
+			UpdatedReplicas:    1,
+			ConfigHash:         "hash",
+			LastTransitionTime: now,
+		},
+		{
+			Name:               "DataNode-ssd",
+			Kind:               "DataNode",
+			SyncStatus:         "Blocked",
+			Message:            "Wait for Master",
+			LastTransitionTime: before,
+		},
+	}, buildComponentStatuses([]components.Component{master, dataNode}, statuses, previous, now))
+}
+
+func TestDegradedBackoff(t *testing.T) {
+	now := time.Now()
+	degradedSince := func(d time.Duration) ytv1.ComponentStatus {
+		return ytv1.ComponentStatus{
+			SyncStatus:         string(components.SyncStatusDegraded),
+			LastTransitionTime: metav1.NewTime(now.Add(-d)),
+		}
+	}
+
+	require.Equal(t, minDegradedBackoff, getDegradedBackoff([]ytv1.ComponentStatus{degradedSince(time.Second)}, now))
+	require.Equal(t, time.Minute, getDegradedBackoff([]ytv1.ComponentStatus{
+		degradedSince(time.Minute),
+		degradedSince(time.Hour),
+		{SyncStatus: string(components.SyncStatusReady), LastTransitionTime: metav1.NewTime(now)},
+	}, now))
+	require.Equal(t, maxDegradedBackoff, getDegradedBackoff([]ytv1.ComponentStatus{degradedSince(time.Hour)}, now))
+}
+
+func TestDegradedCondition(t *testing.T) {
+	require.Equal(t, metav1.ConditionFalse, getDegradedCondition(nil, nil).Status)
+
+	condition := getDegradedCondition([]string{"YtsaurusClient", "TabletNode"}, nil)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, "Failed to evaluate status of components: YtsaurusClient, TabletNode", condition.Message)
+
+	healthy := getHealthyCondition(nil)
+	require.Equal(t, metav1.ConditionFalse, getDegradedCondition(nil, &healthy).Status)
+
+	unhealthy := getHealthyCondition([]string{"There are master alerts: 1"})
+	condition = getDegradedCondition([]string{"YtsaurusClient"}, &unhealthy)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, "There are master alerts: 1; Failed to evaluate status of components: YtsaurusClient", condition.Message)
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+const defaultHealthCheckPeriod = time.Minute
+
+func getHealthCheckPeriod(resource *ytv1.Ytsaurus) time.Duration {
+	if resource.Spec.HealthCheckPeriod != nil {
+		return resource.Spec.HealthCheckPeriod.Duration
+	}
+	return defaultHealthCheckPeriod
+}
+
+func getHealthyCondition(failedChecks []string) metav1.Condition {
+	if len(failedChecks) == 0 {
+		return metav1.Condition{
+			Type:    consts.ConditionHealthy,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HealthChecksPassed",
+			Message: "All health checks passed",
+		}
+	}
+	return metav1.Condition{
+		Type:    consts.ConditionHealthy,
+		Status:  metav1.ConditionFalse,
+		Reason:  "HealthChecksFailed",
+		Message: strings.Join(failedChecks, "; "),
+	}
+}
+
+// withHealthCheck requeues the reconciliation not later than the next health check is due.
+func withHealthCheck(result, healthResult ctrl.Result) ctrl.Result {
+	if healthResult.RequeueAfter <= 0 || (result.Requeue && result.RequeueAfter <= 0) {
+		return result
+	}
+	if result.RequeueAfter <= 0 || healthResult.RequeueAfter < result.RequeueAfter {
+		result.RequeueAfter = healthResult.RequeueAfter
+	}
+	return result
+}
+
+// checkHealth periodically runs the health checks of the running cluster.
+// The result is reported by the Healthy and Degraded conditions, changes of the health are recorded as events.
+func (r *YtsaurusReconciler) checkHealth(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+	componentManager *ComponentManager,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	resource := ytsaurus.GetResource()
+
+	period := getHealthCheckPeriod(resource)
+	if period <= 0 {
+		return ctrl.Result{}, nil
+	}
+	if lastCheck := resource.Status.LastHealthCheckTime; lastCheck != nil {
+		if untilNextCheck := time.Until(lastCheck.Add(period)); untilNextCheck > 0 {
+			return ctrl.Result{RequeueAfter: untilNextCheck}, nil
+		}
+	}
+
+	failedChecks, err := componentManager.ytsaurusClient.CheckHealth(ctx)
+	if err != nil {
+		logger.Error(err, "failed to check Ytsaurus health")
+		failedChecks = []string{fmt.Sprintf("Failed to check health: %v", err)}
+	}
+
+	healthy := getHealthyCondition(failedChecks)
+	switch {
+	case healthy.Status == metav1.ConditionFalse && !ytsaurus.IsStatusConditionFalse(consts.ConditionHealthy):
+		logger.Info("Ytsaurus is not healthy", "failedChecks", failedChecks)
+		ytsaurus.APIProxy().RecordWarning("Unhealthy", healthy.Message)
+	case healthy.Status == metav1.ConditionTrue && ytsaurus.IsStatusConditionFalse(consts.ConditionHealthy):
+		logger.Info("Ytsaurus is healthy again")
+		ytsaurus.APIProxy().RecordNormal("Healthy", healthy.Message)
+	}
+
+	ytsaurus.SetStatusCondition(healthy)
+	ytsaurus.SetStatusCondition(getDegradedCondition(componentManager.status.degradedComponents, &healthy))
+	now := metav1.Now()
+	resource.Status.LastHealthCheckTime = &now
+	if err := ytsaurus.APIProxy().UpdateStatus(ctx); err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{RequeueAfter: period}, nil
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.ytsaurus.tech/yt/go/yt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+type fakeYtsaurusClient struct {
+	fakeComponent
+	failedChecks []string
+	checks       int
+}
+
+func (c *fakeYtsaurusClient) GetYtClient() yt.Client { return nil }
+
+func (c *fakeYtsaurusClient) CheckHealth(ctx context.Context) ([]string, error) {
+	c.checks++
+	return c.failedChecks, nil
+}
+
+func newTestRunningYtsaurus(t *testing.T) (*apiProxy.Ytsaurus, *record.FakeRecorder) {
+	resource := &ytv1.Ytsaurus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ytsaurus",
+			Namespace: "default",
+		},
+		Status: ytv1.YtsaurusStatus{
+			State: ytv1.ClusterStateRunning,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, ytv1.AddToScheme(scheme))
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource).Build()
+	recorder := record.NewFakeRecorder(100)
+	return apiProxy.NewYtsaurus(resource, client, recorder, scheme), recorder
+}
+
+func TestCheckHealth(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus, recorder := newTestRunningYtsaurus(t)
+	yc := &fakeYtsaurusClient{
+		fakeComponent: fakeComponent{name: "YtsaurusClient"},
+		failedChecks:  []string{"There are lost vital chunks: 3"},
+	}
+	componentManager := &ComponentManager{ytsaurus: ytsaurus, ytsaurusClient: yc}
+	r := &YtsaurusReconciler{}
+
+	result, err := r.checkHealth(ctx, ytsaurus, componentManager)
+	require.NoError(t, err)
+	require.Equal(t, defaultHealthCheckPeriod, result.RequeueAfter)
+	require.True(t, ytsaurus.IsStatusConditionFalse(consts.ConditionHealthy))
+	require.True(t, ytsaurus.IsStatusConditionTrue(consts.ConditionDegraded))
+	require.Contains(t, <-recorder.Events, "There are lost vital chunks: 3")
+
+	// The cluster is not checked again until the period passes.
+	yc.failedChecks = nil
+	result, err = r.checkHealth(ctx, ytsaurus, componentManager)
+	require.NoError(t, err)
+	require.NotZero(t, result.RequeueAfter)
+	require.Equal(t, 1, yc.checks)
+
+	lastCheck := metav1.NewTime(time.Now().Add(-2 * defaultHealthCheckPeriod))
+	ytsaurus.GetResource().Status.LastHealthCheckTime = &lastCheck
+	_, err = r.checkHealth(ctx, ytsaurus, componentManager)
+	require.NoError(t, err)
+	require.Equal(t, 2, yc.checks)
+	require.True(t, ytsaurus.IsStatusConditionTrue(consts.ConditionHealthy))
+	require.True(t, ytsaurus.IsStatusConditionFalse(consts.ConditionDegraded))
+	require.Contains(t, <-recorder.Events, "Healthy")
+}
+
+func TestCheckHealthDisabled(t *testing.T) {
+	ytsaurus, _ := newTestRunningYtsaurus(t)
+	ytsaurus.GetResource().Spec.HealthCheckPeriod = &metav1.Duration{}
+	yc := &fakeYtsaurusClient{fakeComponent: fakeComponent{name: "YtsaurusClient"}}
+
+	r := &YtsaurusReconciler{}
+	result, err := r.checkHealth(context.Background(), ytsaurus, &ComponentManager{ytsaurus: ytsaurus, ytsaurusClient: yc})
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.Zero(t, yc.checks)
+}
+
+func TestWithHealthCheck(t *testing.T) {
+	healthResult := ctrl.Result{RequeueAfter: time.Minute}
+
+	require.Equal(t, healthResult, withHealthCheck(ctrl.Result{}, healthResult))
+	require.Equal(t, ctrl.Result{Requeue: true}, withHealthCheck(ctrl.Result{Requeue: true}, healthResult))
+	require.Equal(t, ctrl.Result{RequeueAfter: time.Second}, withHealthCheck(ctrl.Result{RequeueAfter: time.Second}, healthResult))
+	require.Equal(t, healthResult, withHealthCheck(ctrl.Result{RequeueAfter: time.Hour}, healthResult))
+	require.Equal(t, ctrl.Result{RequeueAfter: time.Hour}, withHealthCheck(ctrl.Result{RequeueAfter: time.Hour}, ctrl.Result{}))
+}
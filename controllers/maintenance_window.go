@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+// waitForMaintenanceWindow postpones the start of the update until one of spec.maintenanceWindows is open.
+// The pending update is reported by the UpdatePending condition.
+func (r *YtsaurusReconciler) waitForMaintenanceWindow(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+) (*ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	windows := ytsaurus.GetResource().Spec.MaintenanceWindows
+	if windows == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	open, nextStart, err := windows.GetMaintenanceWindowState(now)
+	if err != nil {
+		logger.Error(err, "failed to evaluate maintenance windows")
+		return &ctrl.Result{}, err
+	}
+	if open {
+		return nil, nil
+	}
+
+	message := "No maintenance window is scheduled"
+	result := ctrl.Result{}
+	if !nextStart.IsZero() {
+		message = fmt.Sprintf("Update will start in the maintenance window at %s", nextStart.Format(time.RFC3339))
+		result.RequeueAfter = nextStart.Sub(now)
+	}
+	logger.Info("Ytsaurus update is postponed until the maintenance window", "nextWindowStart", nextStart)
+
+	if !ytsaurus.IsStatusConditionTrue(consts.ConditionUpdatePending) {
+		ytsaurus.APIProxy().RecordNormal("Update", message)
+	}
+	ytsaurus.SetStatusCondition(metav1.Condition{
+		Type:    consts.ConditionUpdatePending,
+		Status:  metav1.ConditionTrue,
+		Reason:  "OutsideMaintenanceWindow",
+		Message: message,
+	})
+	err = ytsaurus.APIProxy().UpdateStatus(ctx)
+	return &result, err
+}
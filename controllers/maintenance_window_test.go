@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func newTestMaintenanceWindows(timeZone string, windows ...ytv1.MaintenanceWindowSpec) *ytv1.MaintenanceWindowsSpec {
+	return &ytv1.MaintenanceWindowsSpec{TimeZone: timeZone, Windows: windows}
+}
+
+func newTestMaintenanceWindow(schedule string, duration time.Duration) ytv1.MaintenanceWindowSpec {
+	return ytv1.MaintenanceWindowSpec{Schedule: schedule, Duration: metav1.Duration{Duration: duration}}
+}
+
+func TestGetMaintenanceWindowState(t *testing.T) {
+	// 2024-01-06 is a Saturday.
+	saturday := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+	nightly := newTestMaintenanceWindows("", newTestMaintenanceWindow("0 2 * * *", 2*time.Hour))
+
+	for _, tc := range []struct {
+		name      string
+		windows   *ytv1.MaintenanceWindowsSpec
+		now       time.Time
+		open      bool
+		nextStart time.Time
+	}{
+		{
+			name:      "before the window",
+			windows:   nightly,
+			now:       saturday.Add(time.Hour),
+			nextStart: saturday.Add(2 * time.Hour),
+		},
+		{
+			name:    "at the window start",
+			windows: nightly,
+			now:     saturday.Add(2 * time.Hour),
+			open:    true,
+		},
+		{
+			name:    "inside the window",
+			windows: nightly,
+			now:     saturday.Add(3*time.Hour + 59*time.Minute),
+			open:    true,
+		},
+		{
+			name:      "after the window",
+			windows:   nightly,
+			now:       saturday.Add(4 * time.Hour),
+			nextStart: saturday.Add(26 * time.Hour),
+		},
+		{
+			name:    "window wraps around midnight",
+			windows: newTestMaintenanceWindows("", newTestMaintenanceWindow("0 23 * * FRI", 3*time.Hour)),
+			now:     saturday.Add(time.Hour),
+			open:    true,
+		},
+		{
+			name:      "window wraps around the week",
+			windows:   newTestMaintenanceWindows("", newTestMaintenanceWindow("0 23 * * SAT", 3*time.Hour)),
+			now:       saturday.Add(2 * time.Hour),
+			nextStart: saturday.Add(23 * time.Hour),
+		},
+		{
+			name: "nearest of several windows",
+			windows: newTestMaintenanceWindows("",
+				newTestMaintenanceWindow("0 6 * * *", time.Hour),
+				newTestMaintenanceWindow("0 5 * * *", time.Hour)),
+			now:       saturday.Add(time.Hour),
+			nextStart: saturday.Add(5 * time.Hour),
+		},
+		{
+			// 02:00 in Amsterdam is 01:00 UTC in winter.
+			name:    "window in the time zone",
+			windows: newTestMaintenanceWindows("Europe/Amsterdam", newTestMaintenanceWindow("0 2 * * *", time.Hour)),
+			now:     saturday.Add(time.Hour + 30*time.Minute),
+			open:    true,
+		},
+		{
+			name:      "window in the time zone is not open yet",
+			windows:   newTestMaintenanceWindows("Europe/Amsterdam", newTestMaintenanceWindow("0 2 * * *", time.Hour)),
+			now:       saturday.Add(30 * time.Minute),
+			nextStart: saturday.Add(time.Hour),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			open, nextStart, err := tc.windows.GetMaintenanceWindowState(tc.now)
+			require.NoError(t, err)
+			require.Equal(t, tc.open, open)
+			if tc.nextStart.IsZero() {
+				require.True(t, nextStart.IsZero())
+			} else {
+				require.True(t, tc.nextStart.Equal(nextStart), "next start %s, expected %s", nextStart, tc.nextStart)
+			}
+		})
+	}
+
+	_, _, err := newTestMaintenanceWindows("Mars/Olympus", newTestMaintenanceWindow("0 2 * * *", time.Hour)).
+		GetMaintenanceWindowState(saturday)
+	require.Error(t, err)
+}
+
+func TestWaitForMaintenanceWindow(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	r := &YtsaurusReconciler{Recorder: record.NewFakeRecorder(100)}
+
+	result, err := r.waitForMaintenanceWindow(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	// The window starts at the beginning of the next hour.
+	nextHour := (time.Now().UTC().Hour() + 1) % 24
+	ytsaurus.GetResource().Spec.MaintenanceWindows = newTestMaintenanceWindows("",
+		newTestMaintenanceWindow(fmt.Sprintf("0 %d * * *", nextHour), time.Minute))
+	result, err = r.waitForMaintenanceWindow(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.NotZero(t, result.RequeueAfter)
+	require.True(t, ytsaurus.IsStatusConditionTrue(consts.ConditionUpdatePending))
+}
@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.ytsaurus.tech/yt/go/guid"
+	"go.ytsaurus.tech/yt/go/yt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+// nodeMaintenanceCommentPrefix marks the maintenance requests of cluster nodes added by the operator,
+// the name of the Kubernetes node follows it.
+const nodeMaintenanceCommentPrefix = "Maintenance of Kubernetes node "
+
+// nodeMaintenanceKind describes the maintenance of the cluster nodes of a component.
+type nodeMaintenanceKind struct {
+	componentLabel string
+	rpcPort        int
+	types          []yt.MaintenanceType
+}
+
+var nodeMaintenanceKinds = []nodeMaintenanceKind{
+	{consts.YTComponentLabelDataNode, consts.DataNodeRPCPort, []yt.MaintenanceType{yt.MaintenanceTypeDecommission}},
+	{consts.YTComponentLabelExecNode, consts.ExecNodeRPCPort, []yt.MaintenanceType{yt.MaintenanceTypeDisableSchedulerJobs}},
+	{consts.YTComponentLabelTabletNode, consts.TabletNodeRPCPort, []yt.MaintenanceType{yt.MaintenanceTypeDisableTabletCells}},
+}
+
+// getNodeMaintenanceKind returns the maintenance kind of the pod, nil means that the pod is not a cluster node.
+// Component labels of named node groups are suffixed with the group name.
+func getNodeMaintenanceKind(pod *corev1.Pod) *nodeMaintenanceKind {
+	componentLabel := pod.Labels["app.kubernetes.io/component"]
+	for i := range nodeMaintenanceKinds {
+		kind := &nodeMaintenanceKinds[i]
+		if componentLabel == kind.componentLabel || strings.HasPrefix(componentLabel, kind.componentLabel+"-") {
+			return kind
+		}
+	}
+	return nil
+}
+
+// isNodeInMaintenance checks whether the node is cordoned, e.g. by kubectl drain.
+func isNodeInMaintenance(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeMaintenanceReconciler puts the cluster nodes running on cordoned Kubernetes nodes under YTsaurus maintenance,
+// so they stop receiving chunks, jobs and tablet cells before their pods are evicted.
+// The maintenance is removed when the Kubernetes node is uncordoned or the pod is moved to another node.
+type NodeMaintenanceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// getYtClient returns the client of the running cluster, nil means that the cluster isn't ready yet.
+	getYtClient func(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) (yt.Client, error)
+}
+
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
+func (r *NodeMaintenanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var node corev1.Node
+	if err := r.Get(ctx, req.NamespacedName, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Pods of the removed node are recreated on other nodes, where the maintenance is removed.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	inMaintenance := isNodeInMaintenance(&node)
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.HasLabels{consts.YTComponentLabelName}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	clusterPods := make(map[types.NamespacedName][]*corev1.Pod)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != node.Name || getNodeMaintenanceKind(pod) == nil {
+			continue
+		}
+		cluster := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels["app.kubernetes.io/instance"]}
+		clusterPods[cluster] = append(clusterPods[cluster], pod)
+	}
+
+	var result ctrl.Result
+	for cluster, pods := range clusterPods {
+		ready, err := r.syncClusterMaintenance(ctx, cluster, &node, inMaintenance, pods)
+		if err != nil {
+			logger.Error(err, "failed to sync maintenance of cluster nodes", "ytsaurus", cluster)
+			return ctrl.Result{Requeue: true}, err
+		}
+		if !ready {
+			logger.Info("cluster is not ready for maintenance of its nodes", "ytsaurus", cluster)
+			result.RequeueAfter = time.Minute
+		}
+	}
+	return result, nil
+}
+
+// syncClusterMaintenance sets the maintenance of the cluster nodes running in the given pods.
+// It reports false if the cluster isn't ready to do it.
+func (r *NodeMaintenanceReconciler) syncClusterMaintenance(
+	ctx context.Context,
+	cluster types.NamespacedName,
+	node *corev1.Node,
+	inMaintenance bool,
+	pods []*corev1.Pod,
+) (bool, error) {
+	var resource ytv1.Ytsaurus
+	if err := r.Get(ctx, cluster, &resource); err != nil {
+		return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+	}
+	ytsaurus := apiProxy.NewYtsaurus(&resource, r.Client, r.Recorder, r.Scheme)
+
+	getYtClient := r.getYtClient
+	if getYtClient == nil {
+		getYtClient = getCachedYtClient
+	}
+	ytClient, err := getYtClient(ctx, ytsaurus)
+	if err != nil || ytClient == nil {
+		return false, err
+	}
+
+	comment := nodeMaintenanceCommentPrefix + node.Name
+	clusterDomain := getClusterDomain(r.Client)
+	for _, pod := range pods {
+		kind := getNodeMaintenanceKind(pod)
+		address := fmt.Sprintf("%s.%s.%s.svc.%s:%d",
+			pod.Spec.Hostname,
+			pod.Spec.Subdomain,
+			pod.Namespace,
+			clusterDomain,
+			kind.rpcPort)
+		if err := r.syncNodeMaintenance(ctx, ytsaurus, ytClient, address, kind, inMaintenance, comment); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// syncNodeMaintenance adds the maintenance requests of the kind to the cluster node when it is in maintenance
+// and removes the requests added by the operator for other Kubernetes nodes or when the maintenance is over.
+func (r *NodeMaintenanceReconciler) syncNodeMaintenance(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+	ytClient yt.Client,
+	address string,
+	kind *nodeMaintenanceKind,
+	inMaintenance bool,
+	comment string,
+) error {
+	requests, err := components.GetMaintenanceRequests(ctx, ytClient, address)
+	if err != nil {
+		return err
+	}
+
+	var staleIDs []yt.MaintenanceID
+	existingTypes := make(map[yt.MaintenanceType]bool)
+	for id, request := range requests {
+		if !strings.HasPrefix(request.Comment, nodeMaintenanceCommentPrefix) {
+			continue
+		}
+		if inMaintenance && request.Comment == comment {
+			existingTypes[request.Type] = true
+			continue
+		}
+		maintenanceID, err := guid.ParseString(id)
+		if err != nil {
+			return fmt.Errorf("parse maintenance request id %q: %w", id, err)
+		}
+		staleIDs = append(staleIDs, yt.MaintenanceID(maintenanceID))
+	}
+
+	if len(staleIDs) != 0 {
+		_, err := ytClient.RemoveMaintenance(ctx, yt.MaintenanceComponentClusterNode, address, &yt.RemoveMaintenanceOptions{
+			IDs: staleIDs,
+		})
+		if err != nil {
+			return fmt.Errorf("remove maintenance of node %q: %w", address, err)
+		}
+		ytsaurus.APIProxy().RecordNormal("NodeMaintenance", fmt.Sprintf("Maintenance of cluster node %s is removed", address))
+	}
+
+	if !inMaintenance || requests == nil {
+		// Nodes which are not registered yet don't run anything to be moved away.
+		return nil
+	}
+	for _, maintenanceType := range kind.types {
+		if existingTypes[maintenanceType] {
+			continue
+		}
+		_, err := ytClient.AddMaintenance(ctx, yt.MaintenanceComponentClusterNode, address, maintenanceType, comment, nil)
+		if err != nil {
+			return fmt.Errorf("add %s maintenance of node %q: %w", maintenanceType, address, err)
+		}
+		ytsaurus.APIProxy().RecordNormal("NodeMaintenance", fmt.Sprintf("Added %s maintenance of cluster node %s: %s",
+			maintenanceType, address, comment))
+	}
+	return nil
+}
+
+// mapPodToNode requests reconciliation of the node the cluster node pod is scheduled to.
+func mapPodToNode(object client.Object) []reconcile.Request {
+	pod, ok := object.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" || getNodeMaintenanceKind(pod) == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: pod.Spec.NodeName}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeMaintenanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	podScheduled := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Object.(*corev1.Pod).Spec.NodeName != ""
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.(*corev1.Pod).Spec.NodeName != e.ObjectNew.(*corev1.Pod).Spec.NodeName
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+	maintenanceChanged := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isNodeInMaintenance(e.ObjectOld.(*corev1.Node)) != isNodeInMaintenance(e.ObjectNew.(*corev1.Node))
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("node-maintenance").
+		For(&corev1.Node{}, builder.WithPredicates(maintenanceChanged)).
+		Watches(
+			&source.Kind{Type: &corev1.Pod{}},
+			handler.EnqueueRequestsFromMapFunc(mapPodToNode),
+			builder.WithPredicates(podScheduled),
+		).
+		Complete(r)
+}
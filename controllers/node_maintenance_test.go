@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.ytsaurus.tech/yt/go/guid"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+)
+
+func TestGetNodeMaintenanceKind(t *testing.T) {
+	newPod := func(componentLabel string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app.kubernetes.io/component": componentLabel},
+		}}
+	}
+	require.Equal(t, consts.DataNodeRPCPort, getNodeMaintenanceKind(newPod("yt-data-node")).rpcPort)
+	require.Equal(t, consts.ExecNodeRPCPort, getNodeMaintenanceKind(newPod("yt-exec-node-gpu")).rpcPort)
+	require.Nil(t, getNodeMaintenanceKind(newPod("yt-master")))
+	require.Nil(t, getNodeMaintenanceKind(newPod("yt-data-nodes")))
+}
+
+func TestNodeMaintenance(t *testing.T) {
+	t.Setenv("K8S_CLUSTER_DOMAIN", "cluster.local")
+	ctx := context.Background()
+	const address = "dnd-0.data-nodes.default.svc.cluster.local:9012"
+	const comment = "Maintenance of Kubernetes node node-1"
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dnd-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				consts.YTComponentLabelName:   "ytsaurus-yt-data-node",
+				"app.kubernetes.io/component": consts.YTComponentLabelDataNode,
+				"app.kubernetes.io/instance":  "ytsaurus",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1", Hostname: "dnd-0", Subdomain: "data-nodes"},
+	}
+	resource := &ytv1.Ytsaurus{ObjectMeta: metav1.ObjectMeta{Name: "ytsaurus", Namespace: "default"}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ytv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, pod, resource).Build()
+
+	mockCtrl := gomock.NewController(t)
+	ytClient := mock_yt.NewMockClient(mockCtrl)
+	recorder := record.NewFakeRecorder(100)
+	r := &NodeMaintenanceReconciler{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Recorder: recorder,
+		getYtClient: func(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) (yt.Client, error) {
+			return ytClient, nil
+		},
+	}
+	reconcile := func() {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(node)})
+		require.NoError(t, err)
+		require.Zero(t, result)
+	}
+	expectRequests := func(requests map[string]components.MaintenanceRequest) {
+		ytClient.EXPECT().
+			GetNode(gomock.Any(), gomock.Eq(ypath.Path("//sys/cluster_nodes").Child(address).Attr("maintenance_requests")), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				*result.(*map[string]components.MaintenanceRequest) = requests
+				return nil
+			})
+	}
+
+	// Cordoned node gets the maintenance once.
+	expectRequests(map[string]components.MaintenanceRequest{})
+	ytClient.EXPECT().
+		AddMaintenance(gomock.Any(), gomock.Any(), address, yt.MaintenanceTypeDecommission, comment, gomock.Any()).
+		Return(&yt.AddMaintenanceResponse{}, nil)
+	reconcile()
+	require.Contains(t, <-recorder.Events, "Added decommission maintenance of cluster node "+address)
+
+	requests := map[string]components.MaintenanceRequest{
+		"1-2-3-4": {Type: yt.MaintenanceTypeDecommission, Comment: comment},
+		"5-6-7-8": {Type: yt.MaintenanceTypeBan, Comment: "Set by hand"},
+	}
+	expectRequests(requests)
+	reconcile()
+
+	// Only the maintenance added by the operator is removed when the node is uncordoned.
+	node.Spec.Unschedulable = false
+	require.NoError(t, k8sClient.Update(ctx, node))
+	expectRequests(requests)
+	id, err := guid.ParseString("1-2-3-4")
+	require.NoError(t, err)
+	ytClient.EXPECT().
+		RemoveMaintenance(gomock.Any(), gomock.Any(), address, gomock.Eq(&yt.RemoveMaintenanceOptions{
+			IDs: []yt.MaintenanceID{yt.MaintenanceID(id)},
+		})).
+		Return(&yt.RemoveMaintenanceResponse{}, nil)
+	reconcile()
+	require.Contains(t, <-recorder.Events, "Maintenance of cluster node "+address+" is removed")
+}
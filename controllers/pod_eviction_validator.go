@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/resources"
+)
+
+// PodEvictionValidator denies evictions of master and discovery pods which would break their quorum,
+// e.g. during kubectl drain. Unlike pod disruption budgets it checks the actual hydra state of the master cells.
+type PodEvictionValidator struct {
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// getYtClient returns the client of the running cluster, nil means that the cluster isn't ready yet.
+	getYtClient func(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) (yt.Client, error)
+}
+
+//+kubebuilder:webhook:path=/validate-v1-pod-eviction,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=pods/eviction,verbs=create,versions=v1,name=yt-pod-eviction.kb.io,admissionReviewVersions=v1
+
+const apiPathValidatePodEviction = "/validate-v1-pod-eviction"
+
+// hydraStateUnavailable describes a peer whose hydra state can't be read.
+const hydraStateUnavailable = "unavailable"
+
+// maxFollowerLag is the number of mutations a follower may be behind the leader
+// to take the leadership without a long recovery.
+const maxFollowerLag = 1000
+
+func (v *PodEvictionValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(apiPathValidatePodEviction, &admission.Webhook{
+		Handler: admission.HandlerFunc(func(ctx context.Context, req admission.Request) admission.Response {
+			// The name of the evicted pod is the name of the eviction request.
+			return v.validateEviction(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name})
+		}),
+	})
+	return nil
+}
+
+func (v *PodEvictionValidator) validateEviction(ctx context.Context, podName types.NamespacedName) admission.Response {
+	logger := log.FromContext(ctx).WithValues("pod", podName)
+
+	pod := new(corev1.Pod)
+	if err := v.Client.Get(ctx, podName, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Allowed("pod is not found")
+		}
+		logger.Error(err, "get pod")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if _, ok := pod.Labels[consts.YTComponentLabelName]; !ok {
+		return admission.Allowed("pod is not YT component")
+	}
+
+	var (
+		reason string
+		err    error
+	)
+	switch pod.Labels["app.kubernetes.io/component"] {
+	case consts.YTComponentLabelMaster, consts.YTComponentLabelSecondaryMaster:
+		reason, err = v.checkMasterEviction(ctx, pod)
+	case consts.YTComponentLabelDiscovery:
+		reason, err = v.checkDiscoveryEviction(ctx, pod)
+	default:
+		return admission.Allowed("pod is not a quorum member")
+	}
+	if err != nil {
+		logger.Error(err, "check eviction")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if reason != "" {
+		logger.Info("Eviction is denied", "reason", reason)
+		return admission.Denied(reason)
+	}
+	return admission.Allowed("eviction keeps the quorum")
+}
+
+// checkMasterEviction returns the reason to deny the eviction of the master pod, empty if it can be evicted.
+func (v *PodEvictionValidator) checkMasterEviction(ctx context.Context, pod *corev1.Pod) (string, error) {
+	var resource ytv1.Ytsaurus
+	cluster := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels["app.kubernetes.io/instance"]}
+	if err := v.Client.Get(ctx, cluster, &resource); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	ytsaurus := apiProxy.NewYtsaurus(&resource, v.Client, v.Recorder, v.Scheme)
+
+	getYtClient := v.getYtClient
+	if getYtClient == nil {
+		getYtClient = getCachedYtClient
+	}
+	ytClient, err := getYtClient(ctx, ytsaurus)
+	if err != nil || ytClient == nil {
+		// Pods of clusters which are not running are protected by disruption budgets only.
+		return "", err
+	}
+
+	cellPaths := []ypath.Path{"//sys/primary_masters"}
+	if pod.Labels["app.kubernetes.io/component"] == consts.YTComponentLabelSecondaryMaster {
+		cellPaths = nil
+		var cellTags []string
+		if err := ytClient.ListNode(ctx, ypath.Path("//sys/secondary_masters"), &cellTags, nil); err != nil {
+			return "", fmt.Errorf("list secondary master cells: %w", err)
+		}
+		for _, cellTag := range cellTags {
+			cellPaths = append(cellPaths, ypath.Path("//sys/secondary_masters").Child(cellTag))
+		}
+	}
+
+	addressPrefix := fmt.Sprintf("%s.%s.%s.svc.%s:",
+		pod.Spec.Hostname,
+		pod.Spec.Subdomain,
+		pod.Namespace,
+		getClusterDomain(v.Client))
+	for _, cellPath := range cellPaths {
+		peers, err := components.GetMasterCellHydra(ctx, ytClient, cellPath)
+		if err != nil {
+			return "", err
+		}
+		for address := range peers {
+			if strings.HasPrefix(address, addressPrefix) {
+				return checkMasterCellPeerEviction(pod.Name, address, peers), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// checkMasterCellPeerEviction returns the reason to deny the eviction of the master cell peer, empty if it can be evicted.
+// Eviction of an unhealthy peer doesn't make the cell worse, so it is always allowed.
+func checkMasterCellPeerEviction(podName, address string, peers map[string]*components.MasterHydra) string {
+	isHealthy := func(hydra *components.MasterHydra) bool {
+		return hydra != nil && hydra.Active &&
+			(hydra.State == components.MasterStateLeading || hydra.State == components.MasterStateFollowing)
+	}
+
+	var leader *components.MasterHydra
+	healthyCount := 0
+	for _, hydra := range peers {
+		if isHealthy(hydra) {
+			healthyCount++
+			if hydra.State == components.MasterStateLeading {
+				leader = hydra
+			}
+		}
+	}
+
+	// Unhealthy followers are lagging as well, they have to recover before the leadership can be passed to them.
+	hasLaggingFollower := false
+	for peerAddress, hydra := range peers {
+		if peerAddress == address || hydra == leader {
+			continue
+		}
+		if !isHealthy(hydra) || leader != nil && leader.SequenceNumber-hydra.SequenceNumber > maxFollowerLag {
+			hasLaggingFollower = true
+		}
+	}
+
+	evicted := peers[address]
+	if !isHealthy(evicted) {
+		return ""
+	}
+
+	quorum := len(peers)/2 + 1
+	var problem string
+	switch {
+	case leader == nil:
+		problem = "master cell has no leader"
+	case healthyCount-1 < quorum:
+		problem = fmt.Sprintf("master cell would be left without quorum of %d peers", quorum)
+	case evicted.State == components.MasterStateLeading && hasLaggingFollower:
+		problem = "pod is the leader of the master cell with a lagging follower"
+	default:
+		return ""
+	}
+	return fmt.Sprintf("Eviction of pod %s (%s) is denied: %s, hydra state: %s",
+		podName, describeMasterHydra(evicted), problem, describeMasterCellHydra(peers))
+}
+
+func describeMasterHydra(hydra *components.MasterHydra) string {
+	if hydra == nil {
+		return hydraStateUnavailable
+	}
+	if !hydra.Active {
+		return fmt.Sprintf("%s, inactive", hydra.State)
+	}
+	return string(hydra.State)
+}
+
+func describeMasterCellHydra(peers map[string]*components.MasterHydra) string {
+	states := make([]string, 0, len(peers))
+	for address, hydra := range peers {
+		states = append(states, fmt.Sprintf("%s: %s", address, describeMasterHydra(hydra)))
+	}
+	sort.Strings(states)
+	return strings.Join(states, "; ")
+}
+
+// checkDiscoveryEviction returns the reason to deny the eviction of the discovery pod, empty if it can be evicted.
+// Discovery servers have no hydra, so the majority of their pods is kept ready.
+func (v *PodEvictionValidator) checkDiscoveryEviction(ctx context.Context, pod *corev1.Pod) (string, error) {
+	if !resources.IsPodReady(pod) {
+		return "", nil
+	}
+
+	var pods corev1.PodList
+	err := v.Client.List(ctx, &pods,
+		client.InNamespace(pod.Namespace),
+		client.MatchingLabels{consts.YTComponentLabelName: pod.Labels[consts.YTComponentLabelName]})
+	if err != nil {
+		return "", err
+	}
+
+	readyCount := 0
+	for i := range pods.Items {
+		if resources.IsPodReady(&pods.Items[i]) {
+			readyCount++
+		}
+	}
+
+	quorum := len(pods.Items)/2 + 1
+	if readyCount-1 < quorum {
+		return fmt.Sprintf("Eviction of pod %s is denied: discovery would be left without quorum of %d ready pods, ready pods: %d of %d",
+			pod.Name, quorum, readyCount, len(pods.Items)), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+)
+
+func TestCheckMasterCellPeerEviction(t *testing.T) {
+	leading := &components.MasterHydra{Active: true, State: components.MasterStateLeading}
+	following := &components.MasterHydra{Active: true, State: components.MasterStateFollowing}
+	recovering := &components.MasterHydra{State: "follower_recovery"}
+
+	healthy := map[string]*components.MasterHydra{"ms-0": leading, "ms-1": following, "ms-2": following}
+	require.Empty(t, checkMasterCellPeerEviction("ms-1", "ms-1", healthy))
+	require.Empty(t, checkMasterCellPeerEviction("ms-0", "ms-0", healthy))
+
+	lagging := map[string]*components.MasterHydra{"ms-0": leading, "ms-1": following, "ms-2": recovering}
+	require.Empty(t, checkMasterCellPeerEviction("ms-2", "ms-2", lagging))
+	require.Equal(t,
+		"Eviction of pod ms-1 (following) is denied: master cell would be left without quorum of 2 peers, "+
+			"hydra state: ms-0: leading; ms-1: following; ms-2: follower_recovery, inactive",
+		checkMasterCellPeerEviction("ms-1", "ms-1", lagging))
+
+	five := map[string]*components.MasterHydra{
+		"ms-0": leading, "ms-1": following, "ms-2": following, "ms-3": following, "ms-4": nil,
+	}
+	require.Empty(t, checkMasterCellPeerEviction("ms-1", "ms-1", five))
+	require.Contains(t, checkMasterCellPeerEviction("ms-0", "ms-0", five), "leader of the master cell with a lagging follower")
+	require.Contains(t, checkMasterCellPeerEviction("ms-0", "ms-0", five), "ms-4: unavailable")
+
+	elections := map[string]*components.MasterHydra{"ms-0": following, "ms-1": following, "ms-2": following}
+	require.Contains(t, checkMasterCellPeerEviction("ms-0", "ms-0", elections), "master cell has no leader")
+
+	// Healthy followers are compared with the leader by the sequence number of the applied mutations.
+	leadingAt := func(sequenceNumber int64) *components.MasterHydra {
+		return &components.MasterHydra{Active: true, State: components.MasterStateLeading, SequenceNumber: sequenceNumber}
+	}
+	followingAt := func(sequenceNumber int64) *components.MasterHydra {
+		return &components.MasterHydra{Active: true, State: components.MasterStateFollowing, SequenceNumber: sequenceNumber}
+	}
+	catchingUp := map[string]*components.MasterHydra{"ms-0": leadingAt(5000), "ms-1": followingAt(4990), "ms-2": followingAt(5000)}
+	require.Empty(t, checkMasterCellPeerEviction("ms-0", "ms-0", catchingUp))
+	behind := map[string]*components.MasterHydra{"ms-0": leadingAt(5000), "ms-1": followingAt(3000), "ms-2": followingAt(5000)}
+	require.Contains(t, checkMasterCellPeerEviction("ms-0", "ms-0", behind), "leader of the master cell with a lagging follower")
+	require.Empty(t, checkMasterCellPeerEviction("ms-1", "ms-1", behind))
+}
+
+func TestPodEvictionValidator(t *testing.T) {
+	t.Setenv("K8S_CLUSTER_DOMAIN", "cluster.local")
+	ctx := context.Background()
+
+	newPod := func(name, subdomain, componentLabel string, ready bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					consts.YTComponentLabelName:   "ytsaurus-" + componentLabel,
+					"app.kubernetes.io/component": componentLabel,
+					"app.kubernetes.io/instance":  "ytsaurus",
+				},
+			},
+			Spec: corev1.PodSpec{Hostname: name, Subdomain: subdomain},
+		}
+		if ready {
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return pod
+	}
+	resource := &ytv1.Ytsaurus{ObjectMeta: metav1.ObjectMeta{Name: "ytsaurus", Namespace: "default"}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ytv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			resource,
+			newPod("ms-0", "masters", consts.YTComponentLabelMaster, true),
+			newPod("ds-0", "discovery", consts.YTComponentLabelDiscovery, true),
+			newPod("ds-1", "discovery", consts.YTComponentLabelDiscovery, false),
+			newPod("ds-2", "discovery", consts.YTComponentLabelDiscovery, true),
+			newPod("hp-0", "http-proxies", consts.YTComponentLabelHTTPProxy, true),
+		).
+		Build()
+
+	mockCtrl := gomock.NewController(t)
+	ytClient := mock_yt.NewMockClient(mockCtrl)
+	v := &PodEvictionValidator{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+		getYtClient: func(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) (yt.Client, error) {
+			return ytClient, nil
+		},
+	}
+	validate := func(name string) (bool, string) {
+		response := v.validateEviction(ctx, types.NamespacedName{Namespace: "default", Name: name})
+		return response.Allowed, string(response.Result.Reason)
+	}
+
+	allowed, _ := validate("hp-0")
+	require.True(t, allowed)
+
+	allowed, reason := validate("ds-0")
+	require.False(t, allowed)
+	require.Contains(t, reason, "Eviction of pod ds-0 is denied")
+	allowed, _ = validate("ds-1")
+	require.True(t, allowed)
+
+	// The only master can't be evicted without losing the quorum.
+	const address = "ms-0.masters.default.svc.cluster.local:9010"
+	cellPath := ypath.Path("//sys/primary_masters")
+	ytClient.EXPECT().
+		ListNode(gomock.Any(), gomock.Eq(cellPath), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.ListNodeOptions) error {
+			*result.(*[]string) = []string{address}
+			return nil
+		})
+	ytClient.EXPECT().
+		GetNode(gomock.Any(), gomock.Eq(cellPath.JoinChild(address, "orchid", "monitoring", "hydra")), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+			*result.(*components.MasterHydra) = components.MasterHydra{Active: true, State: components.MasterStateLeading}
+			return nil
+		})
+	allowed, reason = validate("ms-0")
+	require.False(t, allowed)
+	require.Equal(t,
+		"Eviction of pod ms-0 (leading) is denied: master cell would be left without quorum of 1 peers, "+
+			"hydra state: "+address+": leading",
+		reason)
+}
@@ -30,7 +30,11 @@ func (r *SpytReconciler) Sync(ctx context.Context, resource *ytv1.Spyt, ytsaurus
 		return ctrl.Result{}, nil
 	}
 
-	componentStatus := component.Status(ctx)
+	componentStatus, err := component.Status(ctx)
+	if err != nil {
+		logger.Error(err, "failed to evaluate SPYT status")
+		return ctrl.Result{Requeue: true}, err
+	}
 
 	if componentStatus.SyncStatus == components.SyncStatusBlocked {
 		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
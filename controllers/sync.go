@@ -2,10 +2,15 @@ package controllers
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -14,222 +19,15 @@ import (
 	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
 )
 
-func (r *YtsaurusReconciler) handleUpdatingStateFullMode(
-	ctx context.Context,
-	ytsaurus *apiProxy.Ytsaurus,
-	componentManager *ComponentManager,
-) (*ctrl.Result, error) {
+func getCancelledUpdateOutcome(ytsaurus *apiProxy.Ytsaurus) (ytv1.UpdateOutcome, string) {
 	resource := ytsaurus.GetResource()
-
-	switch resource.Status.UpdateStatus.State {
-	case ytv1.UpdateStateNone:
-		ytsaurus.LogUpdate(ctx, "Checking the possibility of updating")
-		err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStatePossibilityCheck)
-		return &ctrl.Result{Requeue: true}, err
-
-	case ytv1.UpdateStatePossibilityCheck:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionHasPossibility) {
-			ytsaurus.LogUpdate(ctx, "Waiting for safe mode enabled")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForSafeModeEnabled)
-			return &ctrl.Result{Requeue: true}, err
-		} else if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNoPossibility) {
-			ytsaurus.LogUpdate(ctx, "Update is impossible, need to apply previous images")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateImpossibleToStart)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateImpossibleToStart:
-		if !componentManager.needSync() || !ytsaurus.GetResource().Spec.EnableFullUpdate {
-			ytsaurus.LogUpdate(ctx, "Spec changed back or full update isn't enabled, update is canceling")
-			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateCancelUpdate)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForSafeModeEnabled:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionSafeModeEnabled) {
-			ytsaurus.LogUpdate(ctx, "Waiting for tablet cells saving")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForTabletCellsSaving)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForTabletCellsSaving:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionTabletCellsSaved) {
-			ytsaurus.LogUpdate(ctx, "Waiting for tablet cells removing to start")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForTabletCellsRemovingStart)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForTabletCellsRemovingStart:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionTabletCellsRemovingStarted) {
-			ytsaurus.LogUpdate(ctx, "Waiting for tablet cells removing to finish")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForTabletCellsRemoved)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForTabletCellsRemoved:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionTabletCellsRemoved) {
-			ytsaurus.LogUpdate(ctx, "Waiting for snapshots")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForSnapshots)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForSnapshots:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionSnaphotsSaved) {
-			ytsaurus.LogUpdate(ctx, "Waiting for pods removal")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForPodsRemoval)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForPodsRemoval:
-		if componentManager.arePodsRemoved() {
-			ytsaurus.LogUpdate(ctx, "Waiting for pods creation")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForPodsCreation)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForPodsCreation:
-		if componentManager.allReadyOrUpdating() {
-			ytsaurus.LogUpdate(ctx, "All components were recreated")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForMasterExitReadOnly)
-			return &ctrl.Result{RequeueAfter: time.Second * 7}, err
-		}
-
-	case ytv1.UpdateStateWaitingForMasterExitReadOnly:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionMasterExitedReadOnly) {
-			ytsaurus.LogUpdate(ctx, "Masters exited read-only state")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForTabletCellsRecovery)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForTabletCellsRecovery:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionTabletCellsRecovered) {
-			ytsaurus.LogUpdate(ctx, "Waiting for operations archive prepare for updating")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForOpArchiveUpdatingPrepare)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForOpArchiveUpdatingPrepare:
-		if !componentManager.needSchedulerUpdate() ||
-			ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNotNecessaryToUpdateOpArchive) {
-			ytsaurus.LogUpdate(ctx, "Operations archive update was skipped")
-			ytsaurus.LogUpdate(ctx, "Waiting for query tracker state prepare for updating")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForQTStateUpdatingPrepare)
-			return &ctrl.Result{Requeue: true}, err
-		}
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionOpArchivePreparedForUpdating) {
-			ytsaurus.LogUpdate(ctx, "Waiting for operations archive updating to finish")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForOpArchiveUpdate)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForOpArchiveUpdate:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionOpArchiveUpdated) {
-			ytsaurus.LogUpdate(ctx, "Waiting for query tracker state prepare for updating")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForQTStateUpdatingPrepare)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForQTStateUpdatingPrepare:
-		if !componentManager.needQueryTrackerUpdate() {
-			ytsaurus.LogUpdate(ctx, "Query tracker state update was skipped")
-			ytsaurus.LogUpdate(ctx, "Waiting for safe mode disabled")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForSafeModeDisabled)
-			return &ctrl.Result{Requeue: true}, err
-		}
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionQTStatePreparedForUpdating) {
-			ytsaurus.LogUpdate(ctx, "Waiting for query tracker state updating to finish")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForQTStateUpdate)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForQTStateUpdate:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionQTStateUpdated) {
-			ytsaurus.LogUpdate(ctx, "Waiting for safe mode disabled")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForSafeModeDisabled)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForSafeModeDisabled:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionSafeModeDisabled) {
-			ytsaurus.LogUpdate(ctx, "Finishing")
-			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateUpdateFinishing)
-			return &ctrl.Result{Requeue: true}, err
-		}
+	if condition := meta.FindStatusCondition(resource.Status.UpdateStatus.Conditions, consts.ConditionNoPossibility); condition != nil && condition.Status == metav1.ConditionTrue {
+		return ytv1.UpdateOutcomeImpossible, condition.Message
 	}
-
-	return nil, nil
-}
-
-func (r *YtsaurusReconciler) handleUpdatingStateLocalMode(
-	ctx context.Context,
-	ytsaurus *apiProxy.Ytsaurus,
-	componentManager *ComponentManager,
-) (*ctrl.Result, error) {
-	resource := ytsaurus.GetResource()
-
-	switch resource.Status.UpdateStatus.State {
-	case ytv1.UpdateStateNone:
-		ytsaurus.LogUpdate(ctx, "Waiting for pods removal")
-		err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForPodsRemoval)
-		return &ctrl.Result{Requeue: true}, err
-
-	case ytv1.UpdateStateWaitingForPodsRemoval:
-		if componentManager.arePodsRemoved() {
-			ytsaurus.LogUpdate(ctx, "Waiting for pods creation")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForPodsCreation)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForPodsCreation:
-		if componentManager.allReadyOrUpdating() {
-			ytsaurus.LogUpdate(ctx, "All components were recreated")
-			ytsaurus.LogUpdate(ctx, "Waiting for operations archive prepare for updating")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForOpArchiveUpdatingPrepare)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForOpArchiveUpdatingPrepare:
-		if !componentManager.needSchedulerUpdate() || ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNotNecessaryToUpdateOpArchive) {
-			ytsaurus.LogUpdate(ctx, "Operations archive update was skipped")
-			ytsaurus.LogUpdate(ctx, "Waiting for query tracker state prepare for updating")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForQTStateUpdatingPrepare)
-			return &ctrl.Result{Requeue: true}, err
-		}
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionOpArchivePreparedForUpdating) {
-			ytsaurus.LogUpdate(ctx, "Waiting for operations archive updating to finish")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForOpArchiveUpdate)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForOpArchiveUpdate:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionOpArchiveUpdated) {
-			ytsaurus.LogUpdate(ctx, "Waiting for query tracker state prepare for updating")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForQTStateUpdatingPrepare)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForQTStateUpdatingPrepare:
-		if !componentManager.needQueryTrackerUpdate() {
-			ytsaurus.LogUpdate(ctx, "Query tracker state update was skipped")
-			ytsaurus.LogUpdate(ctx, "Finishing")
-			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateUpdateFinishing)
-			return &ctrl.Result{Requeue: true}, err
-		}
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionQTStatePreparedForUpdating) {
-			ytsaurus.LogUpdate(ctx, "Waiting for query tracker state updating to finish")
-			err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForQTStateUpdate)
-			return &ctrl.Result{Requeue: true}, err
-		}
-
-	case ytv1.UpdateStateWaitingForQTStateUpdate:
-		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionQTStateUpdated) {
-			ytsaurus.LogUpdate(ctx, "Finishing")
-			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateUpdateFinishing)
-			return &ctrl.Result{Requeue: true}, err
-		}
+	if condition := meta.FindStatusCondition(resource.Status.Conditions, consts.ConditionUpdateStuck); condition != nil && condition.Status == metav1.ConditionTrue {
+		return ytv1.UpdateOutcomeCancelled, condition.Message
 	}
-
-	return nil, nil
+	return ytv1.UpdateOutcomeCancelled, ""
 }
 
 func getComponentNames(components []components.Component) []string {
@@ -256,6 +54,7 @@ func (r *YtsaurusReconciler) Sync(ctx context.Context, resource *ytv1.Ytsaurus)
 	if err != nil {
 		return ctrl.Result{Requeue: true}, err
 	}
+	metrics.ReportClusterState(resource)
 
 	switch resource.Status.State {
 	case ytv1.ClusterStateCreated:
@@ -272,46 +71,98 @@ func (r *YtsaurusReconciler) Sync(ctx context.Context, resource *ytv1.Ytsaurus)
 		}
 
 	case ytv1.ClusterStateRunning:
+		if resource.Spec.Hibernate {
+			logger.Info("Ytsaurus is going to hibernate")
+			ytsaurus.APIProxy().RecordNormal("Hibernation", "Cluster is going to hibernate")
+			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateHibernating)
+			return ctrl.Result{Requeue: true}, err
+		}
+
+		// The health is checked even if the components need sync,
+		// since the pending update may wait for the maintenance window or be disabled.
+		healthResult, err := r.checkHealth(ctx, ytsaurus, componentManager)
+		if err != nil {
+			return healthResult, err
+		}
+
 		switch {
 		case !componentManager.needSync():
 			logger.Info("Ytsaurus is running and happy")
-			return ctrl.Result{}, nil
+			images := resource.Spec.GetImages()
+			dataNodesInstanceCounts := resource.Spec.GetDataNodesInstanceCounts()
+			if !reflect.DeepEqual(resource.Status.RunningImages, images) ||
+				!reflect.DeepEqual(resource.Status.RunningDataNodesInstanceCounts, dataNodesInstanceCounts) ||
+				resource.Status.UpdatePlan != nil ||
+				ytsaurus.IsStatusConditionTrue(consts.ConditionInvalidVersions) ||
+				ytsaurus.IsStatusConditionTrue(consts.ConditionUpdatePending) ||
+				componentManager.recovered() {
+				resource.Status.RunningImages = images
+				resource.Status.RunningDataNodesInstanceCounts = dataNodesInstanceCounts
+				// There is nothing to update, so the previous plan and pending update conditions are outdated.
+				resource.Status.UpdatePlan = nil
+				meta.RemoveStatusCondition(&resource.Status.Conditions, consts.ConditionInvalidVersions)
+				meta.RemoveStatusCondition(&resource.Status.Conditions, consts.ConditionUpdatePending)
+				if err := ytsaurus.APIProxy().UpdateStatus(ctx); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			return healthResult, nil
 
 		case componentManager.needInit():
 			logger.Info("Ytsaurus needs initialization of some components")
 			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateReconfiguration)
 			return ctrl.Result{Requeue: true}, err
 
+		case isUpdatePlanOnly(resource) && (componentManager.needFullUpdate() || componentManager.needLocalUpdate() != nil):
+			logger.Info("Ytsaurus needs update, but only the update plan is requested")
+			result, err := r.saveUpdatePlan(ctx, ytsaurus, componentManager)
+			return withHealthCheck(result, healthResult), err
+
+		case (componentManager.needFullUpdate() || componentManager.needLocalUpdate() != nil) &&
+			len(getUpdateVersionsErrors(resource)) != 0:
+			result, err := r.blockUpdateWithInvalidVersions(ctx, ytsaurus, getUpdateVersionsErrors(resource))
+			return withHealthCheck(result, healthResult), err
+
 		case componentManager.needFullUpdate():
 			logger.Info("Ytsaurus needs full update")
 			if !ytsaurus.GetResource().Spec.EnableFullUpdate {
 				logger.Info("Full update isn't allowed, ignore it")
-				return ctrl.Result{}, nil
+				return healthResult, nil
+			}
+			if result, err := r.waitForMaintenanceWindow(ctx, ytsaurus); result != nil {
+				return withHealthCheck(*result, healthResult), err
 			}
-			err := ytsaurus.SaveUpdatingClusterState(ctx, nil)
+			err := ytsaurus.SaveUpdatingClusterState(ctx, nil, componentManager.getUpdateReasons())
 			return ctrl.Result{Requeue: true}, err
 
 		case componentManager.needLocalUpdate() != nil:
 			componentNames := getComponentNames(componentManager.needLocalUpdate())
 			logger.Info("Ytsaurus needs local components update", "components", componentNames)
-			err := ytsaurus.SaveUpdatingClusterState(ctx, componentNames)
+			if result, err := r.waitForMaintenanceWindow(ctx, ytsaurus); result != nil {
+				return withHealthCheck(*result, healthResult), err
+			}
+			err := ytsaurus.SaveUpdatingClusterState(ctx, componentNames, componentManager.getUpdateReasons())
 			return ctrl.Result{Requeue: true}, err
 		}
 
+		result, err := componentManager.Sync(ctx)
+		return withHealthCheck(result, healthResult), err
+
 	case ytv1.ClusterStateUpdating:
-		var result *ctrl.Result
-		var err error
-		if ytsaurus.GetLocalUpdatingComponents() != nil {
-			result, err = r.handleUpdatingStateLocalMode(ctx, ytsaurus, componentManager)
-		} else {
-			result, err = r.handleUpdatingStateFullMode(ctx, ytsaurus, componentManager)
+		if result, err := r.waitForUpdateApproval(ctx, ytsaurus); result != nil {
+			return *result, err
 		}
 
-		if result != nil {
+		if result, err := r.handleUpdateTimeout(ctx, ytsaurus); result != nil {
+			return *result, err
+		}
+
+		if result, err := newUpdateFlow(ytsaurus, componentManager).Advance(ctx, ytsaurus); result != nil {
 			return *result, err
 		}
 
 	case ytv1.ClusterStateCancelUpdate:
+		ytsaurus.FinishUpdateHistoryEntry(getCancelledUpdateOutcome(ytsaurus))
 		if err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateNone); err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
@@ -325,6 +176,7 @@ func (r *YtsaurusReconciler) Sync(ctx context.Context, resource *ytv1.Ytsaurus)
 		return ctrl.Result{}, err
 
 	case ytv1.ClusterStateUpdateFinishing:
+		ytsaurus.FinishUpdateHistoryEntry(ytv1.UpdateOutcomeFinished, "")
 		if err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateNone); err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
@@ -337,6 +189,30 @@ func (r *YtsaurusReconciler) Sync(ctx context.Context, resource *ytv1.Ytsaurus)
 		err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateRunning)
 		return ctrl.Result{}, err
 
+	case ytv1.ClusterStateHibernating:
+		if isHibernated(ytsaurus, componentManager) {
+			logger.Info("Ytsaurus is hibernated now")
+			ytsaurus.APIProxy().RecordNormal("Hibernation", "Cluster is hibernated")
+			err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateHibernated)
+			return ctrl.Result{}, err
+		}
+
+		if result, err := newHibernationFlow(ytsaurus, componentManager).Advance(ctx, ytsaurus); result != nil {
+			return *result, err
+		}
+
+	case ytv1.ClusterStateHibernated:
+		if resource.Spec.Hibernate {
+			// Components are not synced, so their pods stay removed.
+			logger.Info("Ytsaurus is hibernated, do nothing")
+			return ctrl.Result{}, nil
+		}
+
+		logger.Info("Ytsaurus is waking up")
+		ytsaurus.APIProxy().RecordNormal("Hibernation", "Cluster is waking up")
+		err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateHibernating)
+		return ctrl.Result{Requeue: true}, err
+
 	case ytv1.ClusterStateReconfiguration:
 		if !componentManager.needInit() {
 			logger.Info("Ytsaurus has reconfigured and is running now")
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+)
+
+// persistentComponentKinds are the kinds of components which volume claims are subject to spec.deletionPolicy.
+var persistentComponentKinds = map[string]bool{
+	"Master":          true,
+	"SecondaryMaster": true,
+	"DataNode":        true,
+}
+
+func getComponentListOptions(resource *ytv1.Ytsaurus, component components.Component) []client.ListOption {
+	l := labeller.Labeller{
+		ObjectMeta:     &resource.ObjectMeta,
+		ComponentLabel: component.GetLabel(),
+	}
+	return l.GetListOptions()
+}
+
+// deleteAll deletes the listed objects, the dependent objects are collected in the background.
+func (r *YtsaurusReconciler) deleteAll(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := r.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	return meta.EachListItem(list, func(object runtime.Object) error {
+		err := r.Delete(ctx, object.(client.Object), client.PropagationPolicy(metav1.DeletePropagationBackground))
+		return client.IgnoreNotFound(err)
+	})
+}
+
+// isStatefulSetVolumeClaim checks that the claim is created by the stateful set from one of its templates.
+// Such claims are named <template>-<stateful set>-<ordinal> and keep only the labels of the template.
+func isStatefulSetVolumeClaim(claim *corev1.PersistentVolumeClaim, sts *appsv1.StatefulSet) bool {
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		prefix := fmt.Sprintf("%s-%s-", template.Name, sts.Name)
+		if !strings.HasPrefix(claim.Name, prefix) {
+			continue
+		}
+		if _, err := strconv.ParseUint(strings.TrimPrefix(claim.Name, prefix), 10, 32); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteVolumeClaims deletes the claims of the stateful sets. The claims which are still in use
+// are protected by kubernetes and go away only after the pods.
+func (r *YtsaurusReconciler) deleteVolumeClaims(ctx context.Context, namespace string, statefulSets *appsv1.StatefulSetList) error {
+	var claims corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &claims, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		for j := range statefulSets.Items {
+			if !isStatefulSetVolumeClaim(claim, &statefulSets.Items[j]) {
+				continue
+			}
+			if err := r.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// removeComponent removes the workloads of the component and reports whether all its pods are gone.
+// Volume claims of the stateful sets are deleted along with them according to spec.deletionPolicy,
+// since the claims can't be found by the component labels.
+func (r *YtsaurusReconciler) removeComponent(
+	ctx context.Context,
+	resource *ytv1.Ytsaurus,
+	component components.Component,
+) (bool, error) {
+	listOptions := getComponentListOptions(resource, component)
+	if resource.Spec.DeletionPolicy == ytv1.DeletionPolicyDelete && persistentComponentKinds[getComponentKind(component.GetName())] {
+		var statefulSets appsv1.StatefulSetList
+		if err := r.List(ctx, &statefulSets, listOptions...); err != nil {
+			return false, err
+		}
+		if err := r.deleteVolumeClaims(ctx, resource.Namespace, &statefulSets); err != nil {
+			return false, err
+		}
+	}
+	if err := r.deleteAll(ctx, &appsv1.StatefulSetList{}, listOptions...); err != nil {
+		return false, err
+	}
+	if err := r.deleteAll(ctx, &appsv1.DeploymentList{}, listOptions...); err != nil {
+		return false, err
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, listOptions...); err != nil {
+		return false, err
+	}
+	return len(pods.Items) == 0, nil
+}
+
+// teardown removes the components of the deleted cluster in the reverse order of the sync,
+// so each component is stopped before the components it depends on. The finalizer is removed at the end,
+// the rest of the objects are collected by their owner references.
+// Unmanaged clusters and components are taken over by hand, so their workloads are left as is.
+func (r *YtsaurusReconciler) teardown(ctx context.Context, resource *ytv1.Ytsaurus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(resource, consts.YtsaurusFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if !resource.Spec.IsManaged {
+		logger.Info("Ytsaurus cluster is not managed by controller, components are not removed")
+		controllerutil.RemoveFinalizer(resource, consts.YtsaurusFinalizer)
+		return ctrl.Result{}, r.Update(ctx, resource)
+	}
+
+	ytsaurus := apiProxy.NewYtsaurus(resource, r.Client, r.Recorder, r.Scheme)
+	componentManager, err := NewComponentManager(ctx, ytsaurus)
+	if err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	for i := len(componentManager.allComponents) - 1; i >= 0; i-- {
+		component := componentManager.allComponents[i]
+		if !components.IsManagedComponent(component) {
+			logger.Info("component is not managed by controller, skip its removal", "component", component.GetName())
+			continue
+		}
+		removed, err := r.removeComponent(ctx, resource, component)
+		if err != nil {
+			logger.Error(err, "failed to remove component", "component", component.GetName())
+			return ctrl.Result{Requeue: true}, err
+		}
+		if !removed {
+			logger.Info("waiting for removal of component pods", "component", component.GetName())
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+	}
+
+	logger.Info("Ytsaurus components are removed", "deletionPolicy", resource.Spec.DeletionPolicy)
+	ytsaurus.APIProxy().RecordNormal("Teardown", "All components are removed")
+	controllerutil.RemoveFinalizer(resource, consts.YtsaurusFinalizer)
+	return ctrl.Result{}, r.Update(ctx, resource)
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ptr "k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func newTestComponentObjectMeta(name, componentLabel string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "default",
+		Labels: map[string]string{
+			consts.YTComponentLabelName: "ytsaurus-" + componentLabel,
+		},
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	t.Setenv("K8S_CLUSTER_DOMAIN", "cluster.local")
+	ctx := context.Background()
+
+	resource := &ytv1.Ytsaurus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ytsaurus",
+			Namespace:  "default",
+			Finalizers: []string{consts.YtsaurusFinalizer},
+		},
+		Spec: ytv1.YtsaurusSpec{
+			IsManaged:      true,
+			DeletionPolicy: ytv1.DeletionPolicyDelete,
+			HTTPProxies:    []ytv1.HTTPProxiesSpec{{Role: consts.DefaultHTTPProxyRole}},
+		},
+	}
+	masterSts := &appsv1.StatefulSet{
+		ObjectMeta: newTestComponentObjectMeta("ms", consts.YTComponentLabelMaster),
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "master-data"}}},
+		},
+	}
+	masterPod := &corev1.Pod{ObjectMeta: newTestComponentObjectMeta("ms-0", consts.YTComponentLabelMaster)}
+	// Claims created by the stateful set have only the labels of the template.
+	masterPVC := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "master-data-ms-0", Namespace: "default"}}
+	otherPVC := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "master-data-ms-backup", Namespace: "default"}}
+	proxyPod := &corev1.Pod{ObjectMeta: newTestComponentObjectMeta("hp-0", consts.YTComponentLabelHTTPProxy)}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ytv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(resource, masterSts, masterPod, masterPVC, otherPVC, proxyPod).
+		Build()
+	require.NoError(t, k8sClient.Delete(ctx, resource))
+
+	r := &YtsaurusReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+	teardown := func() ctrl.Result {
+		var ytsaurus ytv1.Ytsaurus
+		require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), &ytsaurus))
+		require.False(t, ytsaurus.DeletionTimestamp.IsZero())
+		result, err := r.teardown(ctx, &ytsaurus)
+		require.NoError(t, err)
+		return result
+	}
+	exists := func(object client.Object) bool {
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(object), object)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	// Http proxies depend on masters, so they are removed first.
+	require.NotZero(t, teardown().RequeueAfter)
+	require.True(t, exists(masterSts))
+
+	require.True(t, exists(masterPVC))
+
+	require.NoError(t, k8sClient.Delete(ctx, proxyPod))
+	require.NotZero(t, teardown().RequeueAfter)
+	require.False(t, exists(masterSts))
+	require.False(t, exists(masterPVC))
+	require.True(t, exists(otherPVC))
+
+	require.NoError(t, k8sClient.Delete(ctx, masterPod))
+	require.Zero(t, teardown().RequeueAfter)
+	require.True(t, exists(otherPVC))
+	require.False(t, exists(resource))
+}
+
+func TestTeardownUnmanaged(t *testing.T) {
+	t.Setenv("K8S_CLUSTER_DOMAIN", "cluster.local")
+	ctx := context.Background()
+
+	for name, spec := range map[string]ytv1.YtsaurusSpec{
+		"cluster": {
+			IsManaged: false,
+		},
+		"component": {
+			IsManaged:      true,
+			PrimaryMasters: ytv1.MastersSpec{InstanceSpec: ytv1.InstanceSpec{Managed: ptr.Bool(false)}},
+			HTTPProxies:    []ytv1.HTTPProxiesSpec{{Role: consts.DefaultHTTPProxyRole}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resource := &ytv1.Ytsaurus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "ytsaurus",
+					Namespace:  "default",
+					Finalizers: []string{consts.YtsaurusFinalizer},
+				},
+				Spec: spec,
+			}
+			masterSts := &appsv1.StatefulSet{ObjectMeta: newTestComponentObjectMeta("ms", consts.YTComponentLabelMaster)}
+			masterPod := &corev1.Pod{ObjectMeta: newTestComponentObjectMeta("ms-0", consts.YTComponentLabelMaster)}
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			require.NoError(t, ytv1.AddToScheme(scheme))
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(resource, masterSts, masterPod).
+				Build()
+			require.NoError(t, k8sClient.Delete(ctx, resource))
+
+			r := &YtsaurusReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+			var ytsaurus ytv1.Ytsaurus
+			require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), &ytsaurus))
+			result, err := r.teardown(ctx, &ytsaurus)
+			require.NoError(t, err)
+			require.Zero(t, result.RequeueAfter)
+
+			// Masters taken over by hand keep running.
+			require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(masterSts), masterSts))
+			require.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func getUpdateStateApprovedCondition(state ytv1.UpdateState) string {
+	return fmt.Sprintf("%sApproved", state)
+}
+
+func needUpdateStateApproval(resource *ytv1.Ytsaurus, state ytv1.UpdateState) bool {
+	for _, approvalState := range resource.Spec.UpdateApprovalStates {
+		if approvalState == state {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForUpdateApproval pauses the update at the beginning of the states listed in spec.updateApprovalStates
+// until the approval annotation with the name of the current state is set.
+// The annotation is removed once the approval is consumed, so it can't be reused by the next update.
+func (r *YtsaurusReconciler) waitForUpdateApproval(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+) (*ctrl.Result, error) {
+	resource := ytsaurus.GetResource()
+	state := resource.Status.UpdateStatus.State
+
+	if !needUpdateStateApproval(resource, state) ||
+		isUpdateRollback(ytsaurus) ||
+		ytsaurus.IsUpdateStatusConditionTrue(getUpdateStateApprovedCondition(state)) {
+		return nil, nil
+	}
+
+	if resource.Annotations[consts.ApprovedUpdateStateAnnotationName] != string(state) {
+		if !ytsaurus.IsStatusConditionTrue(consts.ConditionWaitingForApproval) {
+			ytsaurus.LogUpdate(ctx, fmt.Sprintf("Waiting for approval of %s", state))
+		}
+		ytsaurus.SetStatusCondition(metav1.Condition{
+			Type:   consts.ConditionWaitingForApproval,
+			Status: metav1.ConditionTrue,
+			Reason: "UpdateStateNotApproved",
+			Message: fmt.Sprintf(
+				"Next step is %s, set annotation %s=%s to proceed",
+				state,
+				consts.ApprovedUpdateStateAnnotationName,
+				state),
+		})
+		err := ytsaurus.APIProxy().UpdateStatus(ctx)
+		return &ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
+	delete(resource.Annotations, consts.ApprovedUpdateStateAnnotationName)
+	if err := r.Update(ctx, resource); err != nil {
+		return &ctrl.Result{Requeue: true}, err
+	}
+
+	ytsaurus.LogUpdate(ctx, fmt.Sprintf("%s was approved", state))
+	// Time spent waiting for the approval doesn't count towards the state timeout.
+	now := metav1.Now()
+	resource.Status.UpdateStatus.StateStartTime = &now
+	ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+		Type:    getUpdateStateApprovedCondition(state),
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateStateApproved",
+		Message: fmt.Sprintf("%s was approved", state),
+	})
+	ytsaurus.SetStatusCondition(metav1.Condition{
+		Type:    consts.ConditionWaitingForApproval,
+		Status:  metav1.ConditionFalse,
+		Reason:  "UpdateStateApproved",
+		Message: fmt.Sprintf("%s was approved", state),
+	})
+	err := ytsaurus.APIProxy().UpdateStatus(ctx)
+	return &ctrl.Result{Requeue: true}, err
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+// conditionStep returns the step which is done when the update status condition is set by the components.
+func conditionStep(ytsaurus *apiProxy.Ytsaurus, state ytv1.UpdateState, message string, condition string) components.UpdateStep {
+	return components.UpdateStep{
+		State:   state,
+		Message: message,
+		IsDone:  func() bool { return ytsaurus.IsUpdateStatusConditionTrue(condition) },
+	}
+}
+
+// skipOnRollback disables the steps which make no sense when the previous images are restored.
+func skipOnRollback(ytsaurus *apiProxy.Ytsaurus, steps []components.UpdateStep) []components.UpdateStep {
+	result := make([]components.UpdateStep, 0, len(steps))
+	for _, step := range steps {
+		shouldRun := step.ShouldRun
+		step.ShouldRun = func() bool {
+			return !isUpdateRollback(ytsaurus) && (shouldRun == nil || shouldRun())
+		}
+		result = append(result, step)
+	}
+	return result
+}
+
+func getPodsRecreationSteps(componentManager *ComponentManager) []components.UpdateStep {
+	return []components.UpdateStep{
+		{
+			State:   ytv1.UpdateStateWaitingForPodsRemoval,
+			Message: "Waiting for pods removal",
+			IsDone:  componentManager.arePodsRemoved,
+		},
+		{
+			State:   ytv1.UpdateStateWaitingForPodsCreation,
+			Message: "Waiting for pods creation",
+			IsDone:  componentManager.allReadyOrUpdating,
+		},
+	}
+}
+
+// getClusterRestartSteps returns the steps which recreate all pods while masters are in read-only state
+// and tablet cells are removed. Safe mode is enabled before them and stays enabled after.
+func getClusterRestartSteps(ytsaurus *apiProxy.Ytsaurus, componentManager *ComponentManager) []components.UpdateStep {
+	podsRecreation := getPodsRecreationSteps(componentManager)
+	// Masters need some time to start before they are asked to exit read-only state.
+	podsRecreation[len(podsRecreation)-1].RequeueAfter = time.Second * 7
+
+	steps := []components.UpdateStep{
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForSafeModeEnabled, "Waiting for safe mode enabled", consts.ConditionSafeModeEnabled),
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForTabletCellsSaving, "Waiting for tablet cells saving", consts.ConditionTabletCellsSaved),
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForTabletCellsRemovingStart, "Waiting for tablet cells removing to start", consts.ConditionTabletCellsRemovingStarted),
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForTabletCellsRemoved, "Waiting for tablet cells removing to finish", consts.ConditionTabletCellsRemoved),
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForSnapshots, "Waiting for snapshots", consts.ConditionSnaphotsSaved),
+	}
+	steps = append(steps, podsRecreation...)
+	steps = append(steps,
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForMasterExitReadOnly, "Waiting for masters exit read-only state", consts.ConditionMasterExitedReadOnly),
+		conditionStep(ytsaurus,
+			ytv1.UpdateStateWaitingForTabletCellsRecovery, "Waiting for tablet cells recovery", consts.ConditionTabletCellsRecovered),
+	)
+	return steps
+}
+
+func getSafeModeDisabledStep(ytsaurus *apiProxy.Ytsaurus) components.UpdateStep {
+	return conditionStep(ytsaurus,
+		ytv1.UpdateStateWaitingForSafeModeDisabled, "Waiting for safe mode disabled", consts.ConditionSafeModeDisabled)
+}
+
+// newFullUpdateFlow returns the flow which recreates all components while masters are in read-only state.
+func newFullUpdateFlow(ytsaurus *apiProxy.Ytsaurus, componentManager *ComponentManager) *components.UpdateFlow {
+	possibilityCheck := conditionStep(ytsaurus,
+		ytv1.UpdateStatePossibilityCheck, "Checking the possibility of updating", consts.ConditionHasPossibility)
+	possibilityCheck.IsFailed = func() bool {
+		return ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNoPossibility)
+	}
+
+	steps := []components.UpdateStep{possibilityCheck}
+	steps = append(steps, getClusterRestartSteps(ytsaurus, componentManager)...)
+	steps = append(steps, skipOnRollback(ytsaurus, componentManager.getUpdateSteps())...)
+	steps = append(steps, getSafeModeDisabledStep(ytsaurus))
+
+	return components.NewUpdateFlow(steps...).
+		WithFailureStep(components.UpdateStep{
+			State:   ytv1.UpdateStateImpossibleToStart,
+			Message: "Update is impossible, need to apply previous images",
+			// The update is canceled when the spec is changed back or full update is disabled.
+			IsDone: func() bool {
+				return !componentManager.needSync() || !ytsaurus.GetResource().Spec.EnableFullUpdate
+			},
+		}).
+		WithRollback(func() bool { return isUpdateRollback(ytsaurus) })
+}
+
+// newLocalUpdateFlow returns the flow which recreates only the updating components.
+func newLocalUpdateFlow(ytsaurus *apiProxy.Ytsaurus, componentManager *ComponentManager) *components.UpdateFlow {
+	steps := getPodsRecreationSteps(componentManager)
+	steps = append(steps, skipOnRollback(ytsaurus, componentManager.getUpdateSteps())...)
+
+	return components.NewUpdateFlow(steps...).
+		WithRollback(func() bool { return isUpdateRollback(ytsaurus) })
+}
+
+// newHibernationFlow returns the flow which shuts the cluster down and brings it back up
+// by the same steps as the full update. The cluster stays hibernated after the pods removal
+// until spec.hibernate is unset, see isHibernated.
+func newHibernationFlow(ytsaurus *apiProxy.Ytsaurus, componentManager *ComponentManager) *components.UpdateFlow {
+	steps := getClusterRestartSteps(ytsaurus, componentManager)
+	steps = append(steps, getSafeModeDisabledStep(ytsaurus))
+	return components.NewUpdateFlow(steps...).
+		WithFinish(func(ctx context.Context) error { return finishHibernation(ctx, ytsaurus) })
+}
+
+// finishHibernation brings the woken up cluster back to running. Hibernation isn't an update,
+// so it isn't recorded in the update history.
+func finishHibernation(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) error {
+	if err := ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateNone); err != nil {
+		return err
+	}
+	if err := ytsaurus.ClearUpdateStatus(ctx); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Ytsaurus has woken up and is running now")
+	ytsaurus.APIProxy().RecordNormal("Hibernation", "Cluster has woken up")
+	return ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateRunning)
+}
+
+// isHibernated checks that the hibernation flow has removed all the pods and the cluster should stay down.
+func isHibernated(ytsaurus *apiProxy.Ytsaurus, componentManager *ComponentManager) bool {
+	return ytsaurus.GetResource().Spec.Hibernate &&
+		ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval &&
+		componentManager.arePodsRemoved()
+}
+
+func newUpdateFlow(ytsaurus *apiProxy.Ytsaurus, componentManager *ComponentManager) *components.UpdateFlow {
+	if ytsaurus.GetLocalUpdatingComponents() != nil {
+		return newLocalUpdateFlow(ytsaurus, componentManager)
+	}
+	return newFullUpdateFlow(ytsaurus, componentManager)
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+)
+
+func newTestUpdatingYtsaurus(t *testing.T, components []string) *apiProxy.Ytsaurus {
+	resource := &ytv1.Ytsaurus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ytsaurus",
+			Namespace: "default",
+		},
+		Spec: ytv1.YtsaurusSpec{
+			EnableFullUpdate: true,
+		},
+		Status: ytv1.YtsaurusStatus{
+			State: ytv1.ClusterStateUpdating,
+			UpdateStatus: ytv1.UpdateStatus{
+				State:      ytv1.UpdateStateNone,
+				Components: components,
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, ytv1.AddToScheme(scheme))
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource).Build()
+	return apiProxy.NewYtsaurus(resource, client, record.NewFakeRecorder(100), scheme)
+}
+
+func setTestUpdateCondition(ytsaurus *apiProxy.Ytsaurus, condition string) {
+	ytsaurus.SetUpdateStatusCondition(context.Background(), metav1.Condition{
+		Type:   condition,
+		Status: metav1.ConditionTrue,
+		Reason: "Test",
+	})
+}
+
+func TestUpdateFlowStatesMatchUpdatePlan(t *testing.T) {
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	componentManager := &ComponentManager{ytsaurus: ytsaurus}
+
+	require.Equal(t, getUpdatePlanStates(true, false, false), newFullUpdateFlow(ytsaurus, componentManager).GetStates())
+	require.Equal(t, getUpdatePlanStates(false, false, false), newLocalUpdateFlow(ytsaurus, componentManager).GetStates())
+}
+
+func TestFullUpdateFlow(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	componentManager := &ComponentManager{ytsaurus: ytsaurus}
+	flow := newUpdateFlow(ytsaurus, componentManager)
+
+	_, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStatePossibilityCheck, ytsaurus.GetUpdateState())
+
+	result, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	setTestUpdateCondition(ytsaurus, consts.ConditionHasPossibility)
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForSafeModeEnabled, ytsaurus.GetUpdateState())
+
+	// Pods are recreated, so masters are given time to start.
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForPodsCreation))
+	componentManager.status.allReadyOrUpdating = true
+	result, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.NotZero(t, result.RequeueAfter)
+	require.Equal(t, ytv1.UpdateStateWaitingForMasterExitReadOnly, ytsaurus.GetUpdateState())
+
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForSafeModeDisabled))
+	setTestUpdateCondition(ytsaurus, consts.ConditionSafeModeDisabled)
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.ClusterStateUpdateFinishing, ytsaurus.GetClusterState())
+}
+
+func TestFullUpdateFlowImpossibleToStart(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	componentManager := &ComponentManager{ytsaurus: ytsaurus, status: ComponentManagerStatus{needSync: true}}
+	flow := newUpdateFlow(ytsaurus, componentManager)
+
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStatePossibilityCheck))
+	setTestUpdateCondition(ytsaurus, consts.ConditionNoPossibility)
+	_, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateImpossibleToStart, ytsaurus.GetUpdateState())
+
+	result, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	// Spec is changed back.
+	componentManager.status.needSync = false
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.ClusterStateCancelUpdate, ytsaurus.GetClusterState())
+}
+
+func TestFullUpdateFlowRollback(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	flow := newUpdateFlow(ytsaurus, &ComponentManager{ytsaurus: ytsaurus})
+
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForTabletCellsRecovery))
+	setTestUpdateCondition(ytsaurus, consts.ConditionUpdateRollback)
+	setTestUpdateCondition(ytsaurus, consts.ConditionTabletCellsRecovered)
+	_, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForSafeModeDisabled, ytsaurus.GetUpdateState())
+
+	setTestUpdateCondition(ytsaurus, consts.ConditionSafeModeDisabled)
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.ClusterStateCancelUpdate, ytsaurus.GetClusterState())
+}
+
+func TestLocalUpdateFlow(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, []string{"Discovery"})
+	componentManager := &ComponentManager{ytsaurus: ytsaurus}
+	flow := newUpdateFlow(ytsaurus, componentManager)
+
+	_, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForPodsRemoval, ytsaurus.GetUpdateState())
+
+	// There are no updating components, so pods are considered removed.
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForPodsCreation, ytsaurus.GetUpdateState())
+
+	result, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	componentManager.status.allReadyOrUpdating = true
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.ClusterStateUpdateFinishing, ytsaurus.GetClusterState())
+}
+
+func TestHibernationFlow(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	ytsaurus.GetResource().Spec.Hibernate = true
+	require.NoError(t, ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateHibernating))
+	componentManager := &ComponentManager{ytsaurus: ytsaurus}
+	flow := newHibernationFlow(ytsaurus, componentManager)
+
+	// Hibernation doesn't check the possibility of the update.
+	_, err := flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForSafeModeEnabled, ytsaurus.GetUpdateState())
+	require.False(t, isHibernated(ytsaurus, componentManager))
+
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForSnapshots))
+	setTestUpdateCondition(ytsaurus, consts.ConditionSnaphotsSaved)
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForPodsRemoval, ytsaurus.GetUpdateState())
+
+	// There are no components, so pods are considered removed.
+	require.True(t, isHibernated(ytsaurus, componentManager))
+
+	// Waking up.
+	ytsaurus.GetResource().Spec.Hibernate = false
+	require.False(t, isHibernated(ytsaurus, componentManager))
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForPodsCreation, ytsaurus.GetUpdateState())
+
+	componentManager.status.allReadyOrUpdating = true
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForMasterExitReadOnly, ytsaurus.GetUpdateState())
+
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForTabletCellsRecovery))
+	setTestUpdateCondition(ytsaurus, consts.ConditionTabletCellsRecovered)
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	require.Equal(t, ytv1.UpdateStateWaitingForSafeModeDisabled, ytsaurus.GetUpdateState())
+
+	setTestUpdateCondition(ytsaurus, consts.ConditionSafeModeDisabled)
+	_, err = flow.Advance(ctx, ytsaurus)
+	require.NoError(t, err)
+	// Hibernation isn't finished as an update.
+	require.Equal(t, ytv1.ClusterStateRunning, ytsaurus.GetClusterState())
+	require.Equal(t, ytv1.UpdateStateNone, ytsaurus.GetUpdateState())
+	require.Empty(t, ytsaurus.GetResource().Status.UpdateStatus.Conditions)
+	require.Empty(t, ytsaurus.GetResource().Status.UpdateHistory)
+}
+
+func TestHibernationRemovesPodsOfRollingUpdateComponents(t *testing.T) {
+	ctx := context.Background()
+	ytsaurus := newTestUpdatingYtsaurus(t, nil)
+	ytsaurus.GetResource().Spec.Hibernate = true
+	require.NoError(t, ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateHibernating))
+	require.NoError(t, ytsaurus.SaveUpdateState(ctx, ytv1.UpdateStateWaitingForPodsRemoval))
+	proxy := &fakeComponent{name: "HttpProxy"}
+	componentManager := &ComponentManager{ytsaurus: ytsaurus, allComponents: []components.Component{proxy}}
+
+	// Pods updated by the rolling update are still running.
+	setTestUpdateCondition(ytsaurus, labeller.GetPodsUpdatedCondition(proxy.GetName()))
+	require.False(t, isHibernated(ytsaurus, componentManager))
+
+	setTestUpdateCondition(ytsaurus, labeller.GetPodsRemovedCondition(proxy.GetName()))
+	require.True(t, isHibernated(ytsaurus, componentManager))
+}
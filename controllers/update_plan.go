@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func isUpdatePlanOnly(resource *ytv1.Ytsaurus) bool {
+	return resource.Annotations[consts.UpdatePlanOnlyAnnotationName] == "true"
+}
+
+func containsComponent(cmps []components.Component, cmp components.Component) bool {
+	for _, c := range cmps {
+		if c == cmp {
+			return true
+		}
+	}
+	return false
+}
+
+// getUpdatePlanStates returns update states which will be run by the update handlers.
+func getUpdatePlanStates(fullUpdate, schedulerUpdate, queryTrackerUpdate bool) []ytv1.UpdateState {
+	var states []ytv1.UpdateState
+	if fullUpdate {
+		states = append(states,
+			ytv1.UpdateStatePossibilityCheck,
+			ytv1.UpdateStateWaitingForSafeModeEnabled,
+			ytv1.UpdateStateWaitingForTabletCellsSaving,
+			ytv1.UpdateStateWaitingForTabletCellsRemovingStart,
+			ytv1.UpdateStateWaitingForTabletCellsRemoved,
+			ytv1.UpdateStateWaitingForSnapshots)
+	}
+	states = append(states,
+		ytv1.UpdateStateWaitingForPodsRemoval,
+		ytv1.UpdateStateWaitingForPodsCreation)
+	if fullUpdate {
+		states = append(states,
+			ytv1.UpdateStateWaitingForMasterExitReadOnly,
+			ytv1.UpdateStateWaitingForTabletCellsRecovery)
+	}
+	if schedulerUpdate {
+		states = append(states,
+			ytv1.UpdateStateWaitingForOpArchiveUpdatingPrepare,
+			ytv1.UpdateStateWaitingForOpArchiveUpdate)
+	}
+	if queryTrackerUpdate {
+		states = append(states,
+			ytv1.UpdateStateWaitingForQTStateUpdatingPrepare,
+			ytv1.UpdateStateWaitingForQTStateUpdate)
+	}
+	if fullUpdate {
+		states = append(states, ytv1.UpdateStateWaitingForSafeModeDisabled)
+	}
+	return states
+}
+
+func buildUpdatePlan(resource *ytv1.Ytsaurus, componentManager *ComponentManager) *ytv1.UpdatePlan {
+	plan := &ytv1.UpdatePlan{
+		CreationTime: metav1.Now(),
+		Mode:         ytv1.UpdateModeLocal,
+	}
+
+	var updatingComponents []components.Component
+	for _, cmp := range componentManager.allComponents {
+		status := componentManager.getComponentStatus(cmp)
+		if status.SyncStatus != components.SyncStatusNeedFullUpdate &&
+			status.SyncStatus != components.SyncStatusNeedLocalUpdate {
+			continue
+		}
+		updatingComponents = append(updatingComponents, cmp)
+		plan.Components = append(plan.Components, ytv1.ComponentUpdatePlan{
+			Name:       cmp.GetName(),
+			SyncStatus: string(status.SyncStatus),
+			Reason:     status.Message,
+		})
+	}
+
+	fullUpdate := componentManager.needFullUpdate()
+	if fullUpdate {
+		plan.Mode = ytv1.UpdateModeFull
+		// All components are recreated during the full update.
+		updatingComponents = componentManager.allComponents
+		if !resource.Spec.EnableFullUpdate {
+			plan.Message = "Full update isn't enabled, update won't be started"
+		}
+	}
+
+	schedulerUpdate := componentManager.schedulerComponent != nil &&
+		containsComponent(updatingComponents, componentManager.schedulerComponent)
+	queryTrackerUpdate := componentManager.queryTrackerComponent != nil &&
+		containsComponent(updatingComponents, componentManager.queryTrackerComponent)
+	plan.States = getUpdatePlanStates(fullUpdate, schedulerUpdate, queryTrackerUpdate)
+
+	return plan
+}
+
+func isSameUpdatePlan(lhs, rhs *ytv1.UpdatePlan) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+	return lhs.Mode == rhs.Mode &&
+		lhs.Message == rhs.Message &&
+		reflect.DeepEqual(lhs.Components, rhs.Components) &&
+		reflect.DeepEqual(lhs.States, rhs.States)
+}
+
+// saveUpdatePlan publishes the plan of the pending update in the status instead of starting the update.
+func (r *YtsaurusReconciler) saveUpdatePlan(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+	componentManager *ComponentManager,
+) (ctrl.Result, error) {
+	resource := ytsaurus.GetResource()
+	plan := buildUpdatePlan(resource, componentManager)
+	if isSameUpdatePlan(resource.Status.UpdatePlan, plan) {
+		return ctrl.Result{}, nil
+	}
+
+	resource.Status.UpdatePlan = plan
+	ytsaurus.APIProxy().RecordNormal("Update", "Update plan was computed, update isn't started because of plan only annotation")
+	err := ytsaurus.APIProxy().UpdateStatus(ctx)
+	return ctrl.Result{}, err
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func getUpdateStateTimedOutCondition(state ytv1.UpdateState) string {
+	return fmt.Sprintf("%sTimedOut", state)
+}
+
+func getUpdateStateTimeout(resource *ytv1.Ytsaurus, state ytv1.UpdateState) *time.Duration {
+	for _, stateTimeout := range resource.Spec.UpdateStateTimeouts {
+		if stateTimeout.State == state {
+			return &stateTimeout.Timeout.Duration
+		}
+	}
+	return nil
+}
+
+func isUpdateRollback(ytsaurus *apiProxy.Ytsaurus) bool {
+	return ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionUpdateRollback)
+}
+
+// getRollbackUpdateState returns the state the update should continue from to be rolled back.
+// UpdateStateNone means that nothing has been changed yet and the update can be just canceled.
+func getRollbackUpdateState(ytsaurus *apiProxy.Ytsaurus) (ytv1.UpdateState, bool) {
+	state := ytsaurus.GetUpdateState()
+
+	if ytsaurus.GetLocalUpdatingComponents() != nil {
+		switch state {
+		case ytv1.UpdateStateNone:
+			return ytv1.UpdateStateNone, true
+		case ytv1.UpdateStateWaitingForPodsRemoval, ytv1.UpdateStateWaitingForPodsCreation:
+			return ytv1.UpdateStateWaitingForPodsCreation, true
+		}
+		return state, false
+	}
+
+	switch state {
+	case ytv1.UpdateStateNone, ytv1.UpdateStatePossibilityCheck, ytv1.UpdateStateImpossibleToStart:
+		return ytv1.UpdateStateNone, true
+	case ytv1.UpdateStateWaitingForSafeModeEnabled, ytv1.UpdateStateWaitingForTabletCellsSaving:
+		return ytv1.UpdateStateWaitingForSafeModeDisabled, true
+	case ytv1.UpdateStateWaitingForTabletCellsRemovingStart:
+		// Tablet cells which were not removed must not be recreated.
+		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionTabletCellsRemovingStarted) {
+			return ytv1.UpdateStateWaitingForTabletCellsRecovery, true
+		}
+		return ytv1.UpdateStateWaitingForSafeModeDisabled, true
+	case ytv1.UpdateStateWaitingForTabletCellsRemoved:
+		return ytv1.UpdateStateWaitingForTabletCellsRecovery, true
+	case ytv1.UpdateStateWaitingForSnapshots:
+		// Masters could have been switched to read-only while building snapshots.
+		if ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionSnapshotsBuildingStarted) {
+			return ytv1.UpdateStateWaitingForMasterExitReadOnly, true
+		}
+		return ytv1.UpdateStateWaitingForTabletCellsRecovery, true
+	case ytv1.UpdateStateWaitingForPodsRemoval, ytv1.UpdateStateWaitingForPodsCreation:
+		return ytv1.UpdateStateWaitingForPodsCreation, true
+	}
+	// Pods have been already recreated with new images.
+	return state, false
+}
+
+// handleUpdateTimeout checks the deadline of the current update state.
+// When the deadline is exceeded the update is marked as stuck and optionally rolled back.
+func (r *YtsaurusReconciler) handleUpdateTimeout(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+) (*ctrl.Result, error) {
+	resource := ytsaurus.GetResource()
+	state := resource.Status.UpdateStatus.State
+	startTime := resource.Status.UpdateStatus.StateStartTime
+
+	timeout := getUpdateStateTimeout(resource, state)
+	if timeout == nil || startTime == nil || isUpdateRollback(ytsaurus) {
+		return nil, nil
+	}
+
+	if time.Now().Before(startTime.Add(*timeout)) ||
+		ytsaurus.IsUpdateStatusConditionTrue(getUpdateStateTimedOutCondition(state)) {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("Update state %s exceeded its timeout %s", state, *timeout)
+	ytsaurus.APIProxy().RecordWarning("Update", message)
+
+	rollbackState, canRollback := getRollbackUpdateState(ytsaurus)
+	rollback := resource.Spec.RollbackStuckUpdate && canRollback && len(resource.Status.RunningImages) != 0
+	if rollback {
+		// Spec update refreshes the whole object, so it goes before status changes.
+		resource.Spec.SetImages(resource.Status.RunningImages)
+		if err := r.Update(ctx, resource); err != nil {
+			return &ctrl.Result{Requeue: true}, err
+		}
+	} else if resource.Spec.RollbackStuckUpdate {
+		ytsaurus.LogUpdate(ctx, fmt.Sprintf("Update can't be rolled back from %s", state))
+	}
+
+	ytsaurus.SetStatusCondition(metav1.Condition{
+		Type:    consts.ConditionUpdateStuck,
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateStateTimeout",
+		Message: message,
+	})
+	ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+		Type:    getUpdateStateTimedOutCondition(state),
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateStateTimeout",
+		Message: message,
+	})
+
+	if !rollback {
+		err := ytsaurus.APIProxy().UpdateStatus(ctx)
+		return &ctrl.Result{Requeue: true}, err
+	}
+
+	ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+		Type:    consts.ConditionUpdateRollback,
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateStateTimeout",
+		Message: "Previous images were restored",
+	})
+
+	if rollbackState == ytv1.UpdateStateNone {
+		ytsaurus.LogUpdate(ctx, "Previous images were restored, update is canceling")
+		err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateCancelUpdate)
+		return &ctrl.Result{Requeue: true}, err
+	}
+
+	ytsaurus.LogUpdate(ctx, fmt.Sprintf("Previous images were restored, rolling back from %s", rollbackState))
+	err := ytsaurus.SaveUpdateState(ctx, rollbackState)
+	return &ctrl.Result{Requeue: true}, err
+}
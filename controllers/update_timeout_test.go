@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func TestGetRollbackUpdateState(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name        string
+		state       ytv1.UpdateState
+		conditions  []string
+		expected    ytv1.UpdateState
+		canRollback bool
+	}{
+		{
+			name:        "nothing is changed yet",
+			state:       ytv1.UpdateStatePossibilityCheck,
+			expected:    ytv1.UpdateStateNone,
+			canRollback: true,
+		},
+		{
+			name:        "tablet cells removing is not started",
+			state:       ytv1.UpdateStateWaitingForTabletCellsRemovingStart,
+			expected:    ytv1.UpdateStateWaitingForSafeModeDisabled,
+			canRollback: true,
+		},
+		{
+			name:        "tablet cells removing is started",
+			state:       ytv1.UpdateStateWaitingForTabletCellsRemovingStart,
+			conditions:  []string{consts.ConditionTabletCellsRemovingStarted},
+			expected:    ytv1.UpdateStateWaitingForTabletCellsRecovery,
+			canRollback: true,
+		},
+		{
+			name:        "tablet cells are being removed",
+			state:       ytv1.UpdateStateWaitingForTabletCellsRemoved,
+			expected:    ytv1.UpdateStateWaitingForTabletCellsRecovery,
+			canRollback: true,
+		},
+		{
+			name:        "snapshots building is started",
+			state:       ytv1.UpdateStateWaitingForSnapshots,
+			conditions:  []string{consts.ConditionSnapshotsBuildingStarted},
+			expected:    ytv1.UpdateStateWaitingForMasterExitReadOnly,
+			canRollback: true,
+		},
+		{
+			name:        "pods are recreated",
+			state:       ytv1.UpdateStateWaitingForMasterExitReadOnly,
+			expected:    ytv1.UpdateStateWaitingForMasterExitReadOnly,
+			canRollback: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ytsaurus := newTestUpdatingYtsaurus(t, nil)
+			require.NoError(t, ytsaurus.SaveUpdateState(ctx, tc.state))
+			for _, condition := range tc.conditions {
+				setTestUpdateCondition(ytsaurus, condition)
+			}
+
+			state, canRollback := getRollbackUpdateState(ytsaurus)
+			require.Equal(t, tc.expected, state)
+			require.Equal(t, tc.canRollback, canRollback)
+		})
+	}
+}
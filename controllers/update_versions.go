@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+// getUpdateVersionsErrors validates versions of the spec against the versions the cluster is running with.
+func getUpdateVersionsErrors(resource *ytv1.Ytsaurus) field.ErrorList {
+	return ytv1.ValidateCoreVersions(resource.GetRunningCoreVersions(), resource.Spec.GetCoreVersions())
+}
+
+// blockUpdateWithInvalidVersions reports the update which can't be started because of the component versions.
+func (r *YtsaurusReconciler) blockUpdateWithInvalidVersions(
+	ctx context.Context,
+	ytsaurus *apiProxy.Ytsaurus,
+	versionErrors field.ErrorList,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	message := versionErrors.ToAggregate().Error()
+	logger.Info("Ytsaurus update is blocked because of invalid versions", "errors", message)
+
+	if !ytsaurus.IsStatusConditionTrue(consts.ConditionInvalidVersions) {
+		ytsaurus.APIProxy().RecordWarning("Update", "Update is blocked: "+message)
+	}
+	ytsaurus.SetStatusCondition(metav1.Condition{
+		Type:    consts.ConditionInvalidVersions,
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateBlocked",
+		Message: message,
+	})
+	err := ytsaurus.APIProxy().UpdateStatus(ctx)
+	return ctrl.Result{}, err
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"go.ytsaurus.tech/yt/go/yt"
+	"go.ytsaurus.tech/yt/go/yt/ythttp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/components"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
+)
+
+// ytsaurusClientComponentName is the name of the ytsaurus client component in the cluster status.
+const ytsaurusClientComponentName = "YtsaurusClient"
+
+type cachedYtClient struct {
+	config   yt.Config
+	ytClient yt.Client
+}
+
+// ytClientCache keeps the clients of the running clusters for the webhooks and the controllers
+// which only talk to the cluster. Unlike the component manager, it doesn't fetch and evaluate the components:
+// the readiness of the cluster is taken from its status, the client is built from the proxy address and the token.
+type ytClientCache struct {
+	mu      sync.Mutex
+	clients map[types.NamespacedName]*cachedYtClient
+}
+
+var runningYtClients = &ytClientCache{clients: make(map[types.NamespacedName]*cachedYtClient)}
+
+// getCachedYtClient returns the client of the running cluster, nil means that the cluster isn't ready yet.
+func getCachedYtClient(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) (yt.Client, error) {
+	return runningYtClients.get(ctx, ytsaurus)
+}
+
+// isYtsaurusClientReady checks that the cluster is running and its ytsaurus client component was ready on the last sync.
+func isYtsaurusClientReady(resource *ytv1.Ytsaurus) bool {
+	if resource.Status.State != ytv1.ClusterStateRunning {
+		return false
+	}
+	for _, status := range resource.Status.Components {
+		if status.Name == ytsaurusClientComponentName {
+			return status.SyncStatus == string(components.SyncStatusReady)
+		}
+	}
+	return false
+}
+
+func (c *ytClientCache) get(ctx context.Context, ytsaurus *apiProxy.Ytsaurus) (yt.Client, error) {
+	resource := ytsaurus.GetResource()
+	cluster := types.NamespacedName{Namespace: resource.Namespace, Name: resource.Name}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !isYtsaurusClientReady(resource) {
+		c.remove(cluster)
+		return nil, nil
+	}
+
+	k8sClient := ytsaurus.APIProxy().Client()
+	l := labeller.Labeller{ObjectMeta: &resource.ObjectMeta, ComponentLabel: consts.YTComponentLabelClient}
+	var secret corev1.Secret
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: resource.Namespace, Name: l.GetSecretName()}, &secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	cfgen := ytconfig.NewGenerator(resource, getClusterDomain(k8sClient))
+	config := components.GetYtClientConfig(cfgen, string(secret.Data[consts.TokenSecretKey]))
+	if cached, ok := c.clients[cluster]; ok && cached.config.Proxy == config.Proxy && cached.config.Token == config.Token {
+		return cached.ytClient, nil
+	}
+
+	c.remove(cluster)
+	ytClient, err := ythttp.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	cached := &cachedYtClient{
+		config:   *config,
+		ytClient: metrics.NewInstrumentedClient(ytClient, resource.Namespace, resource.Name),
+	}
+	c.clients[cluster] = cached
+	return cached.ytClient, nil
+}
+
+func (c *ytClientCache) remove(cluster types.NamespacedName) {
+	if cached, ok := c.clients[cluster]; ok {
+		cached.ytClient.Stop()
+		delete(c.clients, cluster)
+	}
+}
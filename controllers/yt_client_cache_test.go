@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.ytsaurus.tech/yt/go/yt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	apiProxy "github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+func TestYtClientCache(t *testing.T) {
+	t.Setenv("K8S_CLUSTER_DOMAIN", "cluster.local")
+	ctx := context.Background()
+
+	resource := &ytv1.Ytsaurus{
+		ObjectMeta: metav1.ObjectMeta{Name: "ytsaurus", Namespace: "default"},
+		Spec: ytv1.YtsaurusSpec{
+			HTTPProxies: []ytv1.HTTPProxiesSpec{{Role: consts.DefaultHTTPProxyRole}},
+		},
+		Status: ytv1.YtsaurusStatus{State: ytv1.ClusterStateInitializing},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: consts.YTComponentLabelClient + "-secret", Namespace: "default"},
+		Data:       map[string][]byte{consts.TokenSecretKey: []byte("token")},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ytv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource, secret).Build()
+	ytsaurus := apiProxy.NewYtsaurus(resource, k8sClient, record.NewFakeRecorder(100), scheme)
+	cache := &ytClientCache{clients: make(map[types.NamespacedName]*cachedYtClient)}
+	get := func() yt.Client {
+		ytClient, err := cache.get(ctx, ytsaurus)
+		require.NoError(t, err)
+		return ytClient
+	}
+
+	require.Nil(t, get())
+
+	resource.Status.State = ytv1.ClusterStateRunning
+	resource.Status.Components = []ytv1.ComponentStatus{{Name: ytsaurusClientComponentName, SyncStatus: "Blocked"}}
+	require.Nil(t, get())
+
+	resource.Status.Components[0].SyncStatus = "Ready"
+	ytClient := get()
+	require.NotNil(t, ytClient)
+	require.Same(t, ytClient, get())
+
+	// The client is rebuilt when the token is changed.
+	secret.Data[consts.TokenSecretKey] = []byte("new-token")
+	require.NoError(t, k8sClient.Update(ctx, secret))
+	newYtClient := get()
+	require.NotNil(t, newYtClient)
+	require.NotSame(t, ytClient, newYtClient)
+
+	resource.Status.State = ytv1.ClusterStateUpdating
+	require.Nil(t, get())
+	require.Empty(t, cache.clients)
+}
@@ -20,13 +20,18 @@ import (
 	"context"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
 )
 
 // YtsaurusReconciler reconciles a Ytsaurus object
@@ -36,12 +41,6 @@ type YtsaurusReconciler struct {
 	Recorder record.EventRecorder
 }
 
-type updateState struct {
-	status    ytv1.UpdateState
-	condition *string
-	nextState *updateState
-}
-
 // +kubebuilder:rbac:groups=cluster.ytsaurus.tech,resources=ytsaurus,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.ytsaurus.tech,resources=ytsaurus/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.ytsaurus.tech,resources=ytsaurus/finalizers,verbs=update
@@ -54,6 +53,9 @@ func (r *YtsaurusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	var ytsaurus ytv1.Ytsaurus
 	if err := r.Get(ctx, req.NamespacedName, &ytsaurus); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteCluster(req.Namespace, req.Name)
+		}
 		logger.Error(err, "unable to fetch Ytsaurus")
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
@@ -62,6 +64,18 @@ func (r *YtsaurusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 	logger.V(1).Info("found Ytsaurus cluster")
 
+	if !ytsaurus.DeletionTimestamp.IsZero() {
+		logger.Info("Ytsaurus is being deleted")
+		return r.teardown(ctx, &ytsaurus)
+	}
+
+	// Unmanaged cluster is not torn down, so it doesn't need the finalizer.
+	if ytsaurus.Spec.IsManaged && !controllerutil.ContainsFinalizer(&ytsaurus, consts.YtsaurusFinalizer) {
+		controllerutil.AddFinalizer(&ytsaurus, consts.YtsaurusFinalizer)
+		err := r.Update(ctx, &ytsaurus)
+		return ctrl.Result{Requeue: true}, err
+	}
+
 	return r.Sync(ctx, &ytsaurus)
 }
 
@@ -74,5 +88,6 @@ func (r *YtsaurusReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Secret{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Complete(r)
 }
@@ -186,6 +186,26 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	if enableWebhooks && boolEnv("ENABLE_POD_EVICTION_VALIDATOR", true) {
+		if err = (&controllers.PodEvictionValidator{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("pod-eviction-validator"),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PodEviction")
+			os.Exit(1)
+		}
+	}
+	if boolEnv("ENABLE_NODE_MAINTENANCE", true) {
+		if err = (&controllers.NodeMaintenanceReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("node-maintenance-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NodeMaintenance")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -3,6 +3,7 @@ package apiproxy
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,8 +13,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
 )
 
+// maxUpdateHistorySize is the number of the latest updates kept in the status.
+const maxUpdateHistorySize = 10
+
 type Ytsaurus struct {
 	apiProxy APIProxy
 	ytsaurus *ytv1.Ytsaurus
@@ -70,6 +76,8 @@ func (c *Ytsaurus) ClearUpdateStatus(ctx context.Context) error {
 	c.ytsaurus.Status.UpdateStatus.TabletCellBundles = make([]ytv1.TabletCellBundleInfo, 0)
 	c.ytsaurus.Status.UpdateStatus.MasterMonitoringPaths = make([]string, 0)
 	c.ytsaurus.Status.UpdateStatus.Components = nil
+	c.ytsaurus.Status.UpdateStatus.StateStartTime = nil
+	meta.RemoveStatusCondition(&c.ytsaurus.Status.Conditions, consts.ConditionWaitingForApproval)
 	return c.apiProxy.UpdateStatus(ctx)
 }
 
@@ -79,10 +87,15 @@ func (c *Ytsaurus) LogUpdate(ctx context.Context, message string) {
 	logger.Info(fmt.Sprintf("Ytsaurus update: %s", message))
 }
 
-func (c *Ytsaurus) SaveUpdatingClusterState(ctx context.Context, components []string) error {
+func (c *Ytsaurus) SaveUpdatingClusterState(ctx context.Context, components []string, reasons map[string]string) error {
 	logger := log.FromContext(ctx)
 	c.ytsaurus.Status.State = ytv1.ClusterStateUpdating
 	c.ytsaurus.Status.UpdateStatus.Components = components
+	meta.RemoveStatusCondition(&c.ytsaurus.Status.Conditions, consts.ConditionUpdateStuck)
+	meta.RemoveStatusCondition(&c.ytsaurus.Status.Conditions, consts.ConditionInvalidVersions)
+	meta.RemoveStatusCondition(&c.ytsaurus.Status.Conditions, consts.ConditionUpdatePending)
+	c.startUpdateHistoryEntry(components, reasons)
+	c.ytsaurus.Status.UpdatePlan = nil
 
 	if err := c.apiProxy.UpdateStatus(ctx); err != nil {
 		logger.Error(err, "unable to update Ytsaurus cluster status")
@@ -105,6 +118,11 @@ func (c *Ytsaurus) SaveClusterState(ctx context.Context, clusterState ytv1.Clust
 
 func (c *Ytsaurus) SaveUpdateState(ctx context.Context, updateState ytv1.UpdateState) error {
 	logger := log.FromContext(ctx)
+	if c.ytsaurus.Status.UpdateStatus.State != updateState {
+		c.saveUpdateStateDuration()
+		now := metav1.Now()
+		c.ytsaurus.Status.UpdateStatus.StateStartTime = &now
+	}
 	c.ytsaurus.Status.UpdateStatus.State = updateState
 	if err := c.apiProxy.UpdateStatus(ctx); err != nil {
 		logger.Error(err, "unable to update Ytsaurus update state")
@@ -124,3 +142,106 @@ func (c *Ytsaurus) IsStatusConditionTrue(conditionType string) bool {
 func (c *Ytsaurus) IsStatusConditionFalse(conditionType string) bool {
 	return meta.IsStatusConditionFalse(c.ytsaurus.Status.Conditions, conditionType)
 }
+
+func (c *Ytsaurus) startUpdateHistoryEntry(components []string, reasons map[string]string) {
+	entry := ytv1.UpdateHistoryEntry{
+		StartTime:  metav1.Now(),
+		Mode:       ytv1.UpdateModeFull,
+		Components: components,
+		Reasons:    reasons,
+		OldImages:  c.ytsaurus.Status.RunningImages,
+		NewImages:  c.ytsaurus.Spec.GetImages(),
+	}
+	if components != nil {
+		entry.Mode = ytv1.UpdateModeLocal
+	}
+
+	history := append(c.ytsaurus.Status.UpdateHistory, entry)
+	if len(history) > maxUpdateHistorySize {
+		history = history[len(history)-maxUpdateHistorySize:]
+	}
+	c.ytsaurus.Status.UpdateHistory = history
+}
+
+func (c *Ytsaurus) getCurrentUpdateHistoryEntry() *ytv1.UpdateHistoryEntry {
+	history := c.ytsaurus.Status.UpdateHistory
+	if len(history) == 0 || history[len(history)-1].EndTime != nil {
+		return nil
+	}
+	return &history[len(history)-1]
+}
+
+func (c *Ytsaurus) saveUpdateStateDuration() {
+	updateStatus := c.ytsaurus.Status.UpdateStatus
+	if updateStatus.State == ytv1.UpdateStateNone || updateStatus.StateStartTime == nil {
+		return
+	}
+	// Hibernation runs the steps of the update, but it isn't an update.
+	if c.ytsaurus.Status.State == ytv1.ClusterStateHibernating {
+		return
+	}
+	duration := time.Since(updateStatus.StateStartTime.Time)
+	metrics.ObserveUpdateStateDuration(c.ytsaurus, updateStatus.State, duration)
+
+	entry := c.getCurrentUpdateHistoryEntry()
+	if entry == nil {
+		return
+	}
+	entry.StateDurations = append(entry.StateDurations, ytv1.UpdateStateDuration{
+		State:    updateStatus.State,
+		Duration: metav1.Duration{Duration: duration.Round(time.Second)},
+	})
+}
+
+// FinishUpdateHistoryEntry records the outcome of the current update in the update history.
+func (c *Ytsaurus) FinishUpdateHistoryEntry(outcome ytv1.UpdateOutcome, message string) {
+	entry := c.getCurrentUpdateHistoryEntry()
+	if entry == nil {
+		return
+	}
+	c.saveUpdateStateDuration()
+	now := metav1.Now()
+	entry.EndTime = &now
+	entry.Outcome = outcome
+	entry.Message = message
+}
+
+func (c *Ytsaurus) GetDataNodesScaleDown(component string) *ytv1.DataNodesScaleDown {
+	for i := range c.ytsaurus.Status.DataNodesScaleDowns {
+		if c.ytsaurus.Status.DataNodesScaleDowns[i].Component == component {
+			return &c.ytsaurus.Status.DataNodesScaleDowns[i]
+		}
+	}
+	return nil
+}
+
+func (c *Ytsaurus) SetDataNodesScaleDown(scaleDown ytv1.DataNodesScaleDown) {
+	if current := c.GetDataNodesScaleDown(scaleDown.Component); current != nil {
+		*current = scaleDown
+		return
+	}
+	c.ytsaurus.Status.DataNodesScaleDowns = append(c.ytsaurus.Status.DataNodesScaleDowns, scaleDown)
+}
+
+func (c *Ytsaurus) RemoveDataNodesScaleDown(component string) {
+	var scaleDowns []ytv1.DataNodesScaleDown
+	for _, scaleDown := range c.ytsaurus.Status.DataNodesScaleDowns {
+		if scaleDown.Component != component {
+			scaleDowns = append(scaleDowns, scaleDown)
+		}
+	}
+	c.ytsaurus.Status.DataNodesScaleDowns = scaleDowns
+}
+
+// GetRunningDataNodesInstanceCount returns the instance count of the data nodes group the cluster was running with.
+func (c *Ytsaurus) GetRunningDataNodesInstanceCount(group string) (int32, bool) {
+	count, ok := c.ytsaurus.Status.RunningDataNodesInstanceCounts[group]
+	return count, ok
+}
+
+func (c *Ytsaurus) SetRunningDataNodesInstanceCount(group string, count int32) {
+	if c.ytsaurus.Status.RunningDataNodesInstanceCounts == nil {
+		c.ytsaurus.Status.RunningDataNodesInstanceCounts = make(map[string]int32)
+	}
+	c.ytsaurus.Status.RunningDataNodesInstanceCounts[group] = count
+}
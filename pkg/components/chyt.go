@@ -210,13 +210,8 @@ func (c *Chyt) Fetch(ctx context.Context) error {
 	)
 }
 
-func (c *Chyt) Status(ctx context.Context) ComponentStatus {
-	status, err := c.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (c *Chyt) Status(ctx context.Context) (ComponentStatus, error) {
+	return c.doSync(ctx, true)
 }
 
 func (c *Chyt) Sync(ctx context.Context) error {
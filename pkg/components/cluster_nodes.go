@@ -0,0 +1,132 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"go.ytsaurus.tech/library/go/ptr"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	"go.ytsaurus.tech/yt/go/yterrors"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+)
+
+const ClusterNodeStateOnline = "online"
+
+type ClusterNodeState struct {
+	Address string `yson:",value" json:"address"`
+	State   string `yson:"state,attr" json:"state"`
+}
+
+// GetOnlineNodeCount returns the number of the cluster nodes with the given addresses which are online.
+func GetOnlineNodeCount(ctx context.Context, ytClient yt.Client, addresses []string) (int, error) {
+	var nodes []ClusterNodeState
+	// Same as
+	//
+	// 	yt list //sys/cluster_nodes --attribute state
+	//
+	err := ytClient.ListNode(
+		ctx,
+		ypath.Path("//sys/cluster_nodes"),
+		&nodes,
+		&yt.ListNodeOptions{Attributes: []string{"state"}})
+	if err != nil {
+		return 0, fmt.Errorf("list cluster nodes: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		wanted[address] = true
+	}
+
+	onlineCount := 0
+	for _, node := range nodes {
+		if wanted[node.Address] && node.State == ClusterNodeStateOnline {
+			onlineCount++
+		}
+	}
+	return onlineCount, nil
+}
+
+// isMasterReadOnly checks that masters are in read-only state during the cluster restart
+// of the full update or hibernation, the cluster nodes can't register until they exit it.
+func isMasterReadOnly(ytsaurus *apiproxy.Ytsaurus) bool {
+	if !ytv1.IsUpdatingClusterState(ytsaurus.GetClusterState()) || ytsaurus.GetLocalUpdatingComponents() != nil {
+		return false
+	}
+	switch ytsaurus.GetUpdateState() {
+	case ytv1.UpdateStateWaitingForSnapshots,
+		ytv1.UpdateStateWaitingForPodsRemoval,
+		ytv1.UpdateStateWaitingForPodsCreation:
+		return true
+	}
+	return false
+}
+
+// getOnlineNodesStatus checks that the cluster nodes of the server pods are online,
+// at least spec.minReadyInstanceCount of them if it is set. The check is skipped while masters are read-only,
+// otherwise the pods creation step of the update would wait for the nodes forever.
+// The caller waits for the ytsaurus client before the check. Nil status means that enough nodes are online.
+func getOnlineNodesStatus(
+	ctx context.Context,
+	ytsaurus *apiproxy.Ytsaurus,
+	ytClient yt.Client,
+	spec *ytv1.InstanceSpec,
+	getAddress func(ordinal int32) string,
+) (*ComponentStatus, error) {
+	if isMasterReadOnly(ytsaurus) {
+		return nil, nil
+	}
+
+	addresses := make([]string, 0, spec.InstanceCount)
+	for ordinal := int32(0); ordinal < spec.InstanceCount; ordinal++ {
+		addresses = append(addresses, getAddress(ordinal))
+	}
+
+	onlineCount, err := GetOnlineNodeCount(ctx, ytClient, addresses)
+	if err != nil {
+		return ptr.T(WaitingStatus(SyncStatusBlocked, "online nodes")), err
+	}
+
+	minOnlineCount := len(addresses)
+	if spec.MinReadyInstanceCount != nil && *spec.MinReadyInstanceCount < minOnlineCount {
+		minOnlineCount = *spec.MinReadyInstanceCount
+	}
+	if onlineCount < minOnlineCount {
+		return ptr.T(WaitingStatus(
+			SyncStatusBlocked,
+			fmt.Sprintf("online nodes: %d of %d", onlineCount, minOnlineCount),
+		)), nil
+	}
+	return nil, nil
+}
+
+// MaintenanceRequest is an item of the maintenance_requests attribute of the cluster node.
+type MaintenanceRequest struct {
+	Type    yt.MaintenanceType `yson:"type" json:"type"`
+	Comment string             `yson:"comment" json:"comment"`
+}
+
+// GetMaintenanceRequests returns the maintenance requests of the cluster node by their ids.
+// Nodes which are not registered in Cypress have no requests.
+func GetMaintenanceRequests(ctx context.Context, ytClient yt.Client, address string) (map[string]MaintenanceRequest, error) {
+	requests := make(map[string]MaintenanceRequest)
+	// Same as
+	//
+	// 	yt get "//sys/cluster_nodes/$node/@maintenance_requests"
+	//
+	err := ytClient.GetNode(ctx,
+		ypath.Path("//sys/cluster_nodes").Child(address).Attr("maintenance_requests"),
+		&requests,
+		getReadOnlyGetOptions(),
+	)
+	if err != nil {
+		if yterrors.ContainsResolveError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get maintenance requests of node %q: %w", address, err)
+	}
+	return requests, nil
+}
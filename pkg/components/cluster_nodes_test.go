@@ -0,0 +1,89 @@
+package components
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.ytsaurus.tech/library/go/ptr"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+)
+
+var _ = Describe("Online cluster nodes test", func() {
+	var mockYtClient *mock_yt.MockClient
+	var ytsaurus *apiproxy.Ytsaurus
+
+	getAddress := func(ordinal int32) string {
+		return []string{"dnd-0:9012", "dnd-1:9012", "dnd-2:9012"}[ordinal]
+	}
+
+	BeforeEach(func() {
+		mockYtClient = mock_yt.NewMockClient(ctrl)
+
+		resource := &v1.Ytsaurus{
+			ObjectMeta: metav1.ObjectMeta{Name: "ytsaurus", Namespace: "default"},
+			Status:     v1.YtsaurusStatus{State: v1.ClusterStateRunning},
+		}
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource).Build()
+		ytsaurus = apiproxy.NewYtsaurus(resource, client, record.NewFakeRecorder(100), scheme)
+
+		mockYtClient.EXPECT().
+			ListNode(
+				gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/cluster_nodes")),
+				gomock.Any(),
+				gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.ListNodeOptions) error {
+				*result.(*[]ClusterNodeState) = []ClusterNodeState{
+					{Address: "dnd-0:9012", State: "online"},
+					{Address: "dnd-1:9012", State: "registered"},
+					{Address: "end-0:9029", State: "online"},
+				}
+				return nil
+			}).
+			AnyTimes()
+	})
+
+	It("Component is blocked until all nodes are online", func() {
+		spec := &v1.InstanceSpec{InstanceCount: 3}
+		status, err := getOnlineNodesStatus(context.Background(), ytsaurus, mockYtClient, spec, getAddress)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status).Should(Equal(ptr.T(WaitingStatus(SyncStatusBlocked, "online nodes: 1 of 3"))))
+	})
+
+	It("Minimal ready instance count is enough", func() {
+		spec := &v1.InstanceSpec{InstanceCount: 3, MinReadyInstanceCount: ptr.Int(1)}
+		status, err := getOnlineNodesStatus(context.Background(), ytsaurus, mockYtClient, spec, getAddress)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status).Should(BeNil())
+	})
+
+	It("Nodes are not waited for while masters are read-only", func() {
+		spec := &v1.InstanceSpec{InstanceCount: 3}
+		resource := ytsaurus.GetResource()
+		resource.Status.State = v1.ClusterStateUpdating
+		resource.Status.UpdateStatus.State = v1.UpdateStateWaitingForPodsCreation
+
+		status, err := getOnlineNodesStatus(context.Background(), ytsaurus, mockYtClient, spec, getAddress)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status).Should(BeNil())
+
+		// Masters are writable during the local update.
+		resource.Status.UpdateStatus.Components = []string{"DataNode"}
+		status, err = getOnlineNodesStatus(context.Background(), ytsaurus, mockYtClient, spec, getAddress)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status).Should(Equal(ptr.T(WaitingStatus(SyncStatusBlocked, "online nodes: 1 of 3"))))
+	})
+})
@@ -3,9 +3,11 @@ package components
 import (
 	"context"
 	"fmt"
+
 	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
+	"go.ytsaurus.tech/library/go/ptr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,15 +15,22 @@ type SyncStatus string
 
 const (
 	SyncStatusBlocked         SyncStatus = "Blocked"
+	SyncStatusDegraded        SyncStatus = "Degraded"
 	SyncStatusNeedFullUpdate  SyncStatus = "NeedFullUpdate"
 	SyncStatusNeedLocalUpdate SyncStatus = "NeedLocalUpdate"
 	SyncStatusPending         SyncStatus = "Pending"
 	SyncStatusReady           SyncStatus = "Ready"
+	SyncStatusUnmanaged       SyncStatus = "Unmanaged"
 	SyncStatusUpdating        SyncStatus = "Updating"
 )
 
+// IsRunningStatus checks that the component is running, the unmanaged components are considered running
+// since they are taken over by hand.
 func IsRunningStatus(status SyncStatus) bool {
-	return status == SyncStatusReady || status == SyncStatusNeedLocalUpdate || status == SyncStatusNeedFullUpdate
+	return status == SyncStatusReady ||
+		status == SyncStatusNeedLocalUpdate ||
+		status == SyncStatusNeedFullUpdate ||
+		status == SyncStatusUnmanaged
 }
 
 type ComponentStatus struct {
@@ -41,10 +50,15 @@ func SimpleStatus(status SyncStatus) ComponentStatus {
 	return ComponentStatus{status, string(status)}
 }
 
+// DegradedStatus describes the component which status can't be evaluated.
+func DegradedStatus(err error) ComponentStatus {
+	return ComponentStatus{SyncStatusDegraded, fmt.Sprintf("Failed to evaluate status: %s", err)}
+}
+
 type Component interface {
 	Fetch(ctx context.Context) error
 	Sync(ctx context.Context) error
-	Status(ctx context.Context) ComponentStatus
+	Status(ctx context.Context) (ComponentStatus, error)
 	GetName() string
 	GetLabel() string
 	SetReadyCondition(status ComponentStatus)
@@ -53,10 +67,30 @@ type Component interface {
 	IsUpdatable() bool
 }
 
+// ManagedComponent is implemented by the components which can be taken over by hand with managed: false in the spec.
+type ManagedComponent interface {
+	IsManaged() bool
+}
+
+func IsManagedComponent(component Component) bool {
+	managed, ok := component.(ManagedComponent)
+	return !ok || managed.IsManaged()
+}
+
+// DependencyChecker is implemented by the component manager, the dependencies of the components are declared
+// only when the components are registered in it. The components just ask whether their dependencies are running.
+type DependencyChecker interface {
+	// GetBlockingDependency returns the name of the first dependency of the component which isn't running,
+	// the ytsaurus client should be ready. Only the dependencies with the given labels or the labels
+	// of their instance groups are checked if any. Empty name means that nothing blocks the component.
+	GetBlockingDependency(ctx context.Context, component string, labels ...string) string
+}
+
 type componentBase struct {
-	labeller *labeller.Labeller
-	ytsaurus *apiproxy.Ytsaurus
-	cfgen    *ytconfig.Generator
+	labeller     *labeller.Labeller
+	ytsaurus     *apiproxy.Ytsaurus
+	cfgen        *ytconfig.Generator
+	dependencies DependencyChecker
 }
 
 func (c *componentBase) GetName() string {
@@ -79,3 +113,12 @@ func (c *componentBase) SetReadyCondition(status ComponentStatus) {
 		Message: status.Message,
 	})
 }
+
+// waitForDependencies returns the blocked status if some of the dependencies with the given labels aren't running.
+// Nil status means that the component can proceed.
+func (c *componentBase) waitForDependencies(ctx context.Context, labels ...string) *ComponentStatus {
+	if blocking := c.dependencies.GetBlockingDependency(ctx, c.GetName(), labels...); blocking != "" {
+		return ptr.T(WaitingStatus(SyncStatusBlocked, blocking))
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	"go.ytsaurus.tech/yt/go/yson"
@@ -184,6 +186,38 @@ func (h *ConfigHelper) NeedReload() (bool, error) {
 	return false, nil
 }
 
+// GetChangedFileNames returns sorted names of config files which need reload.
+func (h *ConfigHelper) GetChangedFileNames() ([]string, error) {
+	var fileNames []string
+	for fileName := range h.generators {
+		newConfig, err := h.getConfig(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if !cmp.Equal(h.getCurrentConfigValue(fileName), newConfig) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+	return fileNames, nil
+}
+
+// GetConfigHash returns a digest of the generated configs.
+func (h *ConfigHelper) GetConfigHash() (string, error) {
+	fileNames := h.GetFileNames()
+	sort.Strings(fileNames)
+
+	var data []string
+	for _, fileName := range fileNames {
+		config, err := h.getConfig(fileName)
+		if err != nil {
+			return "", err
+		}
+		data = append(data, fileName, string(config))
+	}
+	return sha256String(strings.Join(data, "\n")), nil
+}
+
 func (h *ConfigHelper) NeedInit() bool {
 	if !resources.Exists(h.configMap) {
 		return true
@@ -14,10 +14,9 @@ import (
 type controllerAgent struct {
 	componentBase
 	server server
-	master Component
 }
 
-func NewControllerAgent(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus, master Component) Component {
+func NewControllerAgent(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus, dependencies DependencyChecker) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
 		ObjectMeta:     &resource.ObjectMeta,
@@ -40,12 +39,12 @@ func NewControllerAgent(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus,
 
 	return &controllerAgent{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server: server,
-		master: master,
 	}
 }
 
@@ -53,6 +52,14 @@ func (ca *controllerAgent) IsUpdatable() bool {
 	return true
 }
 
+func (ca *controllerAgent) IsManaged() bool {
+	return ca.server.isManaged()
+}
+
+func (ca *controllerAgent) GetPodsStatus() PodsStatus {
+	return ca.server.getPodsStatus()
+}
+
 func (ca *controllerAgent) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx, ca.server)
 }
@@ -60,18 +67,22 @@ func (ca *controllerAgent) Fetch(ctx context.Context) error {
 func (ca *controllerAgent) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !ca.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(ca.ytsaurus.GetClusterState()) && ca.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, ca.server.getUpdateReason()), err
 	}
 
-	if ca.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(ca.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, ca.ytsaurus, ca, &ca.componentBase, ca.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(ca.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, ca.master.GetName()), err
+	if status := ca.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if ca.server.needSync() {
@@ -88,13 +99,8 @@ func (ca *controllerAgent) doSync(ctx context.Context, dry bool) (ComponentStatu
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (ca *controllerAgent) Status(ctx context.Context) ComponentStatus {
-	status, err := ca.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (ca *controllerAgent) Status(ctx context.Context) (ComponentStatus, error) {
+	return ca.doSync(ctx, true)
 }
 
 func (ca *controllerAgent) Sync(ctx context.Context) error {
@@ -14,8 +14,17 @@ import (
 type dataNode struct {
 	componentBase
 	server server
-	master Component
 
+	// groupName and instanceCount are used to find the nodes to decommission on scale-down,
+	// runningInstanceCount is the instance count the cluster was running with.
+	groupName            string
+	instanceCount        int32
+	runningInstanceCount int32
+
+	instanceSpec       *ytv1.InstanceSpec
+	waitForOnlineState bool
+
+	// yc gives the client to the cluster, its readiness is checked as the dependency.
 	yc   YtsaurusClient
 	rack *rackSetup
 }
@@ -24,7 +33,7 @@ func NewDataNode(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
 	yc YtsaurusClient,
-	master Component,
+	dependencies DependencyChecker,
 	spec ytv1.DataNodesSpec,
 ) Component {
 	resource := ytsaurus.GetResource()
@@ -36,10 +45,20 @@ func NewDataNode(
 		MonitoringPort: consts.DataNodeMonitoringPort,
 	}
 
+	runningInstanceCount, ok := ytsaurus.GetRunningDataNodesInstanceCount(spec.Name)
+	if !ok {
+		runningInstanceCount = spec.InstanceCount
+	}
+	instanceSpec := spec.InstanceSpec
+	if !isScaleDownClusterState(ytsaurus.GetClusterState()) && runningInstanceCount > instanceSpec.InstanceCount {
+		// The nodes to decommission are kept until the scale-down runs, e.g. after the update or wake-up.
+		instanceSpec.InstanceCount = runningInstanceCount
+	}
+
 	server := newServer(
 		&l,
 		ytsaurus,
-		&spec.InstanceSpec,
+		&instanceSpec,
 		"/usr/bin/ytserver-node",
 		"ytserver-data-node.yson",
 		cfgen.GetDataNodesStatefulSetName(spec.Name),
@@ -58,14 +77,21 @@ func NewDataNode(
 
 	return &dataNode{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
-		server: server,
-		master: master,
-		yc:     yc,
-		rack:   rack,
+		server:               server,
+		groupName:            spec.Name,
+		instanceCount:        spec.InstanceCount,
+		runningInstanceCount: runningInstanceCount,
+
+		instanceSpec:       &instanceSpec,
+		waitForOnlineState: spec.WaitForOnlineState,
+
+		yc:   yc,
+		rack: rack,
 	}
 }
 
@@ -73,6 +99,14 @@ func (n *dataNode) IsUpdatable() bool {
 	return true
 }
 
+func (n *dataNode) IsManaged() bool {
+	return n.server.isManaged()
+}
+
+func (n *dataNode) GetPodsStatus() PodsStatus {
+	return n.server.getPodsStatus()
+}
+
 func (n *dataNode) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx, n.server)
 }
@@ -80,18 +114,27 @@ func (n *dataNode) Fetch(ctx context.Context) error {
 func (n *dataNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !n.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
+	// The scale-down goes before the update, so the nodes are decommissioned with the running masters.
+	if status, err := n.handleScaleDown(ctx, dry); status != nil {
+		return *status, err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(n.ytsaurus.GetClusterState()) && n.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedFullUpdate), err
+		return getFullUpdateStatus(n.server), err
 	}
 
-	if n.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(n.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, n.ytsaurus, n, &n.componentBase, n.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(n.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, n.master.GetName()), err
+	if status := n.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if n.server.needSync() {
@@ -105,8 +148,16 @@ func (n *dataNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error
 		return WaitingStatus(SyncStatusBlocked, "pods"), err
 	}
 
-	if n.yc.Status(ctx).SyncStatus != SyncStatusReady {
-		return WaitingStatus(SyncStatusBlocked, n.yc.GetName()), err
+	if status := n.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+		return *status, err
+	}
+	if n.waitForOnlineState {
+		getAddress := func(ordinal int32) string {
+			return n.cfgen.GetDataNodeAddress(n.groupName, ordinal)
+		}
+		if status, err := getOnlineNodesStatus(ctx, n.ytsaurus, n.yc.GetYtClient(), n.instanceSpec, getAddress); status != nil {
+			return *status, err
+		}
 	}
 	if err := n.rack.SetRacks(ctx, n.yc.GetYtClient()); err != nil {
 		return WaitingStatus(SyncStatusBlocked, "rack_awareness"), err
@@ -115,13 +166,8 @@ func (n *dataNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (n *dataNode) Status(ctx context.Context) ComponentStatus {
-	status, err := n.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (n *dataNode) Status(ctx context.Context) (ComponentStatus, error) {
+	return n.doSync(ctx, true)
 }
 
 func (n *dataNode) Sync(ctx context.Context) error {
@@ -0,0 +1,228 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"go.ytsaurus.tech/library/go/ptr"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	"go.ytsaurus.tech/yt/go/yterrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+// isScaleDownClusterState checks whether data nodes can be decommissioned in the cluster state.
+// Masters are read-only during the updates, so the scale-down waits for the running cluster.
+func isScaleDownClusterState(clusterState ytv1.ClusterState) bool {
+	return clusterState == ytv1.ClusterStateRunning || clusterState == ytv1.ClusterStateReconfiguration
+}
+
+// handleScaleDown decommissions the data nodes with the highest ordinals before the statefulset is shrunk,
+// so chunk replicas are moved to the remaining nodes first. The pending status moves the running cluster
+// to reconfiguration before the update is started, and the previous instance count is taken from the status,
+// so the scale-down isn't missed when the statefulset is recreated.
+// Nil status means that there is no scale-down in progress.
+func (n *dataNode) handleScaleDown(ctx context.Context, dry bool) (*ComponentStatus, error) {
+	var err error
+
+	if !isScaleDownClusterState(n.ytsaurus.GetClusterState()) {
+		return nil, err
+	}
+
+	scaleDown := n.ytsaurus.GetDataNodesScaleDown(n.GetName())
+
+	if scaleDown == nil {
+		if n.runningInstanceCount <= n.instanceCount {
+			return nil, err
+		}
+		if status := n.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+			return status, err
+		}
+		if !dry {
+			err = n.startScaleDown(ctx, n.runningInstanceCount)
+		}
+		return ptr.T(WaitingStatus(SyncStatusPending, "data nodes decommission")), err
+	}
+
+	if status := n.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+		return status, err
+	}
+	ytClient := n.yc.GetYtClient()
+
+	if scaleDown.InstanceCount != n.instanceCount {
+		// Instance count was changed during the scale-down, the decommission is reverted
+		// and will be started again for the new instance count if needed.
+		if !dry {
+			err = SetNodesDecommissioned(ctx, ytClient, scaleDown.Nodes, false)
+			if err == nil {
+				n.ytsaurus.RemoveDataNodesScaleDown(n.GetName())
+			}
+		}
+		return ptr.T(WaitingStatus(SyncStatusPending, "data nodes decommission revert")), err
+	}
+
+	if n.server.getReplicas() > n.instanceCount {
+		chunkCount, nodeCount, err := GetStoredChunks(ctx, ytClient, scaleDown.Nodes)
+		if err != nil {
+			return ptr.T(WaitingStatus(SyncStatusPending, "data nodes decommission")), err
+		}
+		if chunkCount > 0 {
+			return ptr.T(WaitingStatus(
+				SyncStatusPending,
+				fmt.Sprintf("data nodes decommission: %d chunks on %d nodes", chunkCount, nodeCount),
+			)), err
+		}
+
+		lostChunks, underreplicatedChunks, err := GetMissingChunkCounts(ctx, ytClient)
+		if err != nil {
+			return ptr.T(WaitingStatus(SyncStatusPending, "chunks replication")), err
+		}
+		if lostChunks > scaleDown.LostChunks || underreplicatedChunks > scaleDown.UnderreplicatedChunks {
+			return ptr.T(WaitingStatus(
+				SyncStatusPending,
+				fmt.Sprintf("chunks replication: %d lost chunks (was %d), %d underreplicated chunks (was %d)",
+					lostChunks, scaleDown.LostChunks, underreplicatedChunks, scaleDown.UnderreplicatedChunks),
+			)), err
+		}
+
+		if !dry {
+			n.ytsaurus.APIProxy().RecordNormal("ScaleDown", fmt.Sprintf("Data nodes %s are decommissioned, scaling statefulset down to %d replicas",
+				n.GetName(), n.instanceCount))
+			err = n.server.Sync(ctx)
+		}
+		return ptr.T(WaitingStatus(SyncStatusPending, "statefulset scale-down")), err
+	}
+
+	removed, err := RemoveOfflineNodes(ctx, ytClient, scaleDown.Nodes)
+	if err != nil || !removed {
+		return ptr.T(WaitingStatus(SyncStatusPending, "decommissioned nodes removal")), err
+	}
+
+	if !dry {
+		n.ytsaurus.RemoveDataNodesScaleDown(n.GetName())
+		n.ytsaurus.SetRunningDataNodesInstanceCount(n.groupName, n.instanceCount)
+	}
+	return ptr.T(WaitingStatus(SyncStatusPending, "decommissioned nodes removal")), err
+}
+
+func (n *dataNode) startScaleDown(ctx context.Context, replicas int32) error {
+	ytClient := n.yc.GetYtClient()
+
+	lostChunks, underreplicatedChunks, err := GetMissingChunkCounts(ctx, ytClient)
+	if err != nil {
+		return err
+	}
+
+	var nodes []string
+	for ordinal := n.instanceCount; ordinal < replicas; ordinal++ {
+		nodes = append(nodes, n.cfgen.GetDataNodeAddress(n.groupName, ordinal))
+	}
+
+	if err := SetNodesDecommissioned(ctx, ytClient, nodes, true); err != nil {
+		return err
+	}
+
+	n.ytsaurus.APIProxy().RecordNormal("ScaleDown", fmt.Sprintf("Data nodes %s scale-down from %d to %d replicas was started",
+		n.GetName(), replicas, n.instanceCount))
+	n.ytsaurus.SetDataNodesScaleDown(ytv1.DataNodesScaleDown{
+		Component:             n.GetName(),
+		InstanceCount:         n.instanceCount,
+		Nodes:                 nodes,
+		LostChunks:            lostChunks,
+		UnderreplicatedChunks: underreplicatedChunks,
+		StartTime:             metav1.Now(),
+	})
+	return nil
+}
+
+// SetNodesDecommissioned sets decommission flag on the cluster nodes.
+// Nodes which are not registered in Cypress are skipped.
+func SetNodesDecommissioned(ctx context.Context, ytClient yt.Client, addresses []string, decommissioned bool) error {
+	for _, address := range addresses {
+		// Same as
+		//
+		// 	yt set "//sys/cluster_nodes/$node/@decommissioned" "%true"
+		//
+		err := ytClient.SetNode(ctx,
+			ypath.Path("//sys/cluster_nodes").Child(address).Attr("decommissioned"),
+			decommissioned,
+			&yt.SetNodeOptions{},
+		)
+		if err != nil && !yterrors.ContainsResolveError(err) {
+			return fmt.Errorf("set decommissioned on node %q: %w", address, err)
+		}
+	}
+	return nil
+}
+
+// GetStoredChunks returns the number of chunks stored on the cluster nodes
+// and the number of nodes that still have chunks.
+func GetStoredChunks(ctx context.Context, ytClient yt.Client, addresses []string) (chunkCount int64, nodeCount int, err error) {
+	for _, address := range addresses {
+		var storedChunks int64
+		err := ytClient.GetNode(ctx,
+			ypath.Path("//sys/cluster_nodes").Child(address).Attr("statistics").Child("total_stored_chunk_count"),
+			&storedChunks,
+			getReadOnlyGetOptions(),
+		)
+		if err != nil {
+			if yterrors.ContainsResolveError(err) {
+				continue
+			}
+			return 0, 0, fmt.Errorf("get stored chunks on node %q: %w", address, err)
+		}
+		if storedChunks > 0 {
+			chunkCount += storedChunks
+			nodeCount++
+		}
+	}
+	return chunkCount, nodeCount, nil
+}
+
+// GetMissingChunkCounts returns the numbers of lost and underreplicated chunks of the cluster.
+func GetMissingChunkCounts(ctx context.Context, ytClient yt.Client) (lostChunks int64, underreplicatedChunks int64, err error) {
+	err = ytClient.GetNode(ctx, ypath.Path("//sys/lost_chunks/@count"), &lostChunks, getReadOnlyGetOptions())
+	if err != nil {
+		return 0, 0, fmt.Errorf("get lost chunks count: %w", err)
+	}
+	err = ytClient.GetNode(ctx, ypath.Path("//sys/underreplicated_chunks/@count"), &underreplicatedChunks, getReadOnlyGetOptions())
+	if err != nil {
+		return 0, 0, fmt.Errorf("get underreplicated chunks count: %w", err)
+	}
+	return lostChunks, underreplicatedChunks, nil
+}
+
+// RemoveOfflineNodes removes the cluster nodes from Cypress.
+// It reports false while some of the nodes are not offline yet.
+func RemoveOfflineNodes(ctx context.Context, ytClient yt.Client, addresses []string) (bool, error) {
+	removed := true
+	for _, address := range addresses {
+		nodePath := ypath.Path("//sys/cluster_nodes").Child(address)
+
+		var state string
+		err := ytClient.GetNode(ctx, nodePath.Attr("state"), &state, getReadOnlyGetOptions())
+		if err != nil {
+			if yterrors.ContainsResolveError(err) {
+				continue
+			}
+			return false, fmt.Errorf("get state of node %q: %w", address, err)
+		}
+		if state != "offline" {
+			removed = false
+			continue
+		}
+
+		// Same as
+		//
+		// 	yt remove "//sys/cluster_nodes/$node"
+		//
+		err = ytClient.RemoveNode(ctx, nodePath, &yt.RemoveNodeOptions{})
+		if err != nil && !yterrors.ContainsResolveError(err) {
+			return false, fmt.Errorf("remove node %q: %w", address, err)
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,167 @@
+package components
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	"go.ytsaurus.tech/yt/go/yterrors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
+)
+
+var _ = Describe("Data nodes scale-down test", func() {
+	var mockYtClient *mock_yt.MockClient
+	addresses := []string{"dnd-3.data-nodes:9012", "dnd-4.data-nodes:9012"}
+
+	BeforeEach(func() {
+		mockYtClient = mock_yt.NewMockClient(ctrl)
+	})
+
+	It("Stored chunks are counted per node", func() {
+		storedChunks := map[string]int64{
+			"//sys/cluster_nodes/dnd-3.data-nodes:9012/@statistics/total_stored_chunk_count": 10,
+			"//sys/cluster_nodes/dnd-4.data-nodes:9012/@statistics/total_stored_chunk_count": 0,
+		}
+		mockYtClient.EXPECT().
+			GetNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(len(addresses)).
+			DoAndReturn(func(_ context.Context, path ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				*result.(*int64) = storedChunks[path.YPath().String()]
+				return nil
+			})
+
+		chunkCount, nodeCount, err := GetStoredChunks(context.Background(), mockYtClient, addresses)
+		Expect(err).Should(Succeed())
+		Expect(chunkCount).Should(Equal(int64(10)))
+		Expect(nodeCount).Should(Equal(1))
+	})
+
+	It("Only offline nodes are removed", func() {
+		states := map[string]string{
+			"//sys/cluster_nodes/dnd-3.data-nodes:9012/@state": "offline",
+			"//sys/cluster_nodes/dnd-4.data-nodes:9012/@state": "online",
+		}
+		mockYtClient.EXPECT().
+			GetNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(len(addresses)).
+			DoAndReturn(func(_ context.Context, path ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				*result.(*string) = states[path.YPath().String()]
+				return nil
+			})
+		mockYtClient.EXPECT().
+			RemoveNode(gomock.Any(), gomock.Eq(ypath.Path("//sys/cluster_nodes/dnd-3.data-nodes:9012")), gomock.Any()).
+			Return(nil)
+
+		removed, err := RemoveOfflineNodes(context.Background(), mockYtClient, addresses)
+		Expect(err).Should(Succeed())
+		Expect(removed).Should(BeFalse())
+	})
+
+	It("Unregistered nodes are considered removed", func() {
+		mockYtClient.EXPECT().
+			GetNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(len(addresses)).
+			Return(yterrors.Err(yterrors.CodeResolveError, "node is not registered"))
+
+		removed, err := RemoveOfflineNodes(context.Background(), mockYtClient, addresses)
+		Expect(err).Should(Succeed())
+		Expect(removed).Should(BeTrue())
+	})
+
+	newTestYtsaurus := func(state v1.ClusterState) (*v1.Ytsaurus, *apiproxy.Ytsaurus, *ytconfig.Generator) {
+		resource := &v1.Ytsaurus{
+			ObjectMeta: metav1.ObjectMeta{Name: "ytsaurus", Namespace: "default"},
+			Spec: v1.YtsaurusSpec{
+				CoreImage: "ytsaurus/ytsaurus:latest",
+				DataNodes: []v1.DataNodesSpec{{Name: consts.DefaultName, InstanceSpec: v1.InstanceSpec{InstanceCount: 3}}},
+			},
+			Status: v1.YtsaurusStatus{
+				State:                          state,
+				RunningDataNodesInstanceCounts: map[string]int32{consts.DefaultName: 3},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(policyv1.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource).Build()
+		ytsaurus := apiproxy.NewYtsaurus(resource, client, record.NewFakeRecorder(100), scheme)
+		return resource, ytsaurus, ytconfig.NewGenerator(resource, "cluster.local")
+	}
+
+	getReplicas := func(ctx context.Context, ytsaurus *apiproxy.Ytsaurus, cfgen *ytconfig.Generator) int32 {
+		var sts appsv1.StatefulSet
+		Expect(ytsaurus.APIProxy().Client().Get(ctx,
+			types.NamespacedName{Namespace: "default", Name: cfgen.GetDataNodesStatefulSetName(consts.DefaultName)},
+			&sts,
+		)).Should(Succeed())
+		return *sts.Spec.Replicas
+	}
+
+	It("Scale-down of the running cluster decommissions nodes in reconfiguration before the update", func() {
+		ctx := context.Background()
+		resource, ytsaurus, cfgen := newTestYtsaurus(v1.ClusterStateRunning)
+		yc := NewFakeYtsaurusClient(mockYtClient)
+		newDataNode := func() Component {
+			dependencies := NewFakeDependencyChecker(NewFakeComponentWithLabel("master", consts.YTComponentLabelMaster), yc)
+			n := NewDataNode(cfgen, ytsaurus, yc, dependencies, resource.Spec.DataNodes[0])
+			Expect(n.Fetch(ctx)).Should(Succeed())
+			return n
+		}
+
+		Expect(newDataNode().(*dataNode).server.Sync(ctx)).Should(Succeed())
+		resource.Spec.CoreImage = "ytsaurus/ytsaurus:new"
+		resource.Spec.DataNodes[0].InstanceCount = 2
+
+		// The pending status makes the component manager move the running cluster to reconfiguration
+		// instead of the update.
+		Expect(getStatus(newDataNode())).Should(Equal(WaitingStatus(SyncStatusPending, "data nodes decommission")))
+		resource.Status.State = v1.ClusterStateReconfiguration
+
+		mockYtClient.EXPECT().
+			GetNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(2).
+			Return(nil)
+		mockYtClient.EXPECT().
+			SetNode(gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/cluster_nodes").Child(cfgen.GetDataNodeAddress(consts.DefaultName, 2)).Attr("decommissioned")),
+				gomock.Eq(true),
+				gomock.Any()).
+			Return(nil)
+		Expect(newDataNode().Sync(ctx)).Should(Succeed())
+		Expect(ytsaurus.GetDataNodesScaleDown(newDataNode().GetName())).ShouldNot(BeNil())
+		Expect(getReplicas(ctx, ytsaurus, cfgen)).Should(Equal(int32(3)))
+	})
+
+	It("Statefulset recreated out of the running cluster keeps the nodes to decommission", func() {
+		ctx := context.Background()
+		resource, ytsaurus, cfgen := newTestYtsaurus(v1.ClusterStateHibernating)
+		resource.Spec.DataNodes[0].InstanceCount = 2
+
+		// No decommission is started while waking up, the mock client fails on any call.
+		yc := NewFakeYtsaurusClient(mockYtClient)
+		dependencies := NewFakeDependencyChecker(NewFakeComponentWithLabel("master", consts.YTComponentLabelMaster), yc)
+		n := NewDataNode(cfgen, ytsaurus, yc, dependencies, resource.Spec.DataNodes[0])
+		Expect(n.Fetch(ctx)).Should(Succeed())
+		Expect(n.(*dataNode).handleScaleDown(ctx, false)).Should(BeNil())
+		Expect(n.(*dataNode).server.Sync(ctx)).Should(Succeed())
+		Expect(getReplicas(ctx, ytsaurus, cfgen)).Should(Equal(int32(3)))
+	})
+})
@@ -51,6 +51,14 @@ func (d *discovery) IsUpdatable() bool {
 	return true
 }
 
+func (d *discovery) IsManaged() bool {
+	return d.server.isManaged()
+}
+
+func (d *discovery) GetPodsStatus() PodsStatus {
+	return d.server.getPodsStatus()
+}
+
 func (d *discovery) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx, d.server)
 }
@@ -58,11 +66,15 @@ func (d *discovery) Fetch(ctx context.Context) error {
 func (d *discovery) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !d.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(d.ytsaurus.GetClusterState()) && d.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, d.server.getUpdateReason()), err
 	}
 
-	if d.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(d.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, d.ytsaurus, d, &d.componentBase, d.server, dry); status != nil {
 			return *status, err
 		}
@@ -82,13 +94,8 @@ func (d *discovery) doSync(ctx context.Context, dry bool) (ComponentStatus, erro
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (d *discovery) Status(ctx context.Context) ComponentStatus {
-	status, err := d.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (d *discovery) Status(ctx context.Context) (ComponentStatus, error) {
+	return d.doSync(ctx, true)
 }
 
 func (d *discovery) Sync(ctx context.Context) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/ytsaurus/yt-k8s-operator/pkg/resources"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	ptr "k8s.io/utils/pointer"
 )
@@ -18,10 +19,16 @@ import (
 type execNode struct {
 	componentBase
 	server     server
-	master     Component
 	sidecars   []string
 	privileged bool
 
+	// drainTimeout enables jobs draining before pods removal, see drainJobs.
+	drainTimeout *metav1.Duration
+
+	groupName          string
+	instanceSpec       *ytv1.InstanceSpec
+	waitForOnlineState bool
+
 	yc   YtsaurusClient
 	rack *rackSetup
 }
@@ -30,7 +37,7 @@ func NewExecNode(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
 	yc YtsaurusClient,
-	master Component,
+	dependencies DependencyChecker,
 	spec ytv1.ExecNodesSpec,
 ) Component {
 	resource := ytsaurus.GetResource()
@@ -64,16 +71,22 @@ func NewExecNode(
 
 	return &execNode{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
-		server:     server,
-		master:     master,
-		sidecars:   spec.Sidecars,
-		privileged: spec.Privileged,
-		yc:         yc,
-		rack:       rack,
+		server:       server,
+		sidecars:     spec.Sidecars,
+		privileged:   spec.Privileged,
+		drainTimeout: spec.DrainTimeout,
+
+		groupName:          spec.Name,
+		instanceSpec:       &spec.InstanceSpec,
+		waitForOnlineState: spec.WaitForOnlineState,
+
+		yc:   yc,
+		rack: rack,
 	}
 }
 
@@ -81,6 +94,14 @@ func (n *execNode) IsUpdatable() bool {
 	return true
 }
 
+func (n *execNode) IsManaged() bool {
+	return n.server.isManaged()
+}
+
+func (n *execNode) GetPodsStatus() PodsStatus {
+	return n.server.getPodsStatus()
+}
+
 func (n *execNode) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx, n.server)
 }
@@ -88,18 +109,34 @@ func (n *execNode) Fetch(ctx context.Context) error {
 func (n *execNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !n.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(n.ytsaurus.GetClusterState()) && n.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, n.server.getUpdateReason()), err
+	}
+
+	if ytv1.IsUpdatingClusterState(n.ytsaurus.GetClusterState()) && IsUpdatingComponent(n.ytsaurus, n) {
+		if n.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval {
+			if n.needJobsDraining() {
+				if status, err := n.drainJobs(ctx, dry); status != nil {
+					return *status, err
+				}
+			}
+		} else if status, err := n.enableSchedulerJobs(ctx, dry); status != nil {
+			return *status, err
+		}
 	}
 
-	if n.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(n.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, n.ytsaurus, n, &n.componentBase, n.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(n.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, n.master.GetName()), err
+	if status := n.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if n.server.needSync() {
@@ -139,8 +176,16 @@ func (n *execNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error
 		return WaitingStatus(SyncStatusBlocked, "pods"), err
 	}
 
-	if n.yc.Status(ctx).SyncStatus != SyncStatusReady {
-		return WaitingStatus(SyncStatusBlocked, n.yc.GetName()), err
+	if status := n.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+		return *status, err
+	}
+	if n.waitForOnlineState {
+		getAddress := func(ordinal int32) string {
+			return n.cfgen.GetExecNodeAddress(n.groupName, ordinal)
+		}
+		if status, err := getOnlineNodesStatus(ctx, n.ytsaurus, n.yc.GetYtClient(), n.instanceSpec, getAddress); status != nil {
+			return *status, err
+		}
 	}
 	if err := n.rack.SetRacks(ctx, n.yc.GetYtClient()); err != nil {
 		return WaitingStatus(SyncStatusBlocked, "rack_awareness"), err
@@ -149,13 +194,8 @@ func (n *execNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (n *execNode) Status(ctx context.Context) ComponentStatus {
-	status, err := n.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (n *execNode) Status(ctx context.Context) (ComponentStatus, error) {
+	return n.doSync(ctx, true)
 }
 
 func (n *execNode) Sync(ctx context.Context) error {
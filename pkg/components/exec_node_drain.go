@@ -0,0 +1,190 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"go.ytsaurus.tech/library/go/ptr"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	"go.ytsaurus.tech/yt/go/yterrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+)
+
+// needJobsDraining reports whether the exec nodes should be drained before their pods are removed.
+// Draining is possible only during a local update, since masters are read-only
+// and scheduler is not working at the pods removal stage of a full update.
+func (n *execNode) needJobsDraining() bool {
+	return n.drainTimeout != nil &&
+		n.ytsaurus.GetLocalUpdatingComponents() != nil &&
+		!n.server.isRollingUpdate()
+}
+
+// drainJobs disables scheduler jobs on the exec nodes and waits for the running jobs to finish.
+// Nil status means that the nodes are drained and the pods can be removed.
+func (n *execNode) drainJobs(ctx context.Context, dry bool) (*ComponentStatus, error) {
+	var err error
+
+	if n.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetJobsDrainedCondition(n.GetName())) {
+		return nil, err
+	}
+
+	// The ytsaurus client is updating too, so just its cluster client is waited for.
+	if n.yc.GetYtClient() == nil {
+		return ptr.T(WaitingStatus(SyncStatusUpdating, n.yc.GetName())), err
+	}
+
+	drainingStarted := meta.FindStatusCondition(
+		n.ytsaurus.GetResource().Status.UpdateStatus.Conditions,
+		labeller.GetJobsDrainingStartedCondition(n.GetName()),
+	)
+	if drainingStarted == nil || drainingStarted.Status != metav1.ConditionTrue {
+		if !dry {
+			err = n.setSchedulerJobsDisabled(ctx, true)
+			if err != nil {
+				return ptr.T(WaitingStatus(SyncStatusUpdating, "scheduler jobs disabling")), err
+			}
+			n.setJobsDrainingCondition(ctx, labeller.GetJobsDrainingStartedCondition(n.GetName()), "Scheduler jobs were disabled")
+		}
+		return ptr.T(WaitingStatus(SyncStatusUpdating, "scheduler jobs disabling")), err
+	}
+
+	jobCount, nodeCount, err := n.getRunningJobs(ctx)
+	if err != nil {
+		return ptr.T(WaitingStatus(SyncStatusUpdating, "jobs draining")), err
+	}
+
+	timedOut := time.Since(drainingStarted.LastTransitionTime.Time) > n.drainTimeout.Duration
+	if jobCount == 0 || timedOut {
+		if !dry {
+			message := "All jobs finished"
+			if jobCount != 0 {
+				message = fmt.Sprintf("Drain timeout exceeded, %d jobs on %d nodes will be aborted", jobCount, nodeCount)
+			}
+			n.setJobsDrainingCondition(ctx, labeller.GetJobsDrainedCondition(n.GetName()), message)
+		}
+		return ptr.T(WaitingStatus(SyncStatusUpdating, "jobs draining")), err
+	}
+
+	return ptr.T(WaitingStatus(SyncStatusUpdating, fmt.Sprintf("jobs draining: %d jobs on %d nodes", jobCount, nodeCount))), err
+}
+
+// enableSchedulerJobs reverts draining after the pods were recreated.
+// Unlike the updating status, the pending one doesn't let the pods creation step finish,
+// so the update isn't finished with the disabled nodes.
+// Nil status means that there is nothing to revert.
+func (n *execNode) enableSchedulerJobs(ctx context.Context, dry bool) (*ComponentStatus, error) {
+	var err error
+
+	if !n.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetJobsDrainingStartedCondition(n.GetName())) ||
+		n.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetSchedulerJobsEnabledCondition(n.GetName())) {
+		return nil, err
+	}
+
+	if n.server.needSync() || !n.server.arePodsReady(ctx) {
+		return nil, err
+	}
+
+	if status := n.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+		return status, err
+	}
+
+	if !dry {
+		err = n.setSchedulerJobsDisabled(ctx, false)
+		if err != nil {
+			return ptr.T(WaitingStatus(SyncStatusPending, "scheduler jobs enabling")), err
+		}
+		n.setJobsDrainingCondition(ctx, labeller.GetSchedulerJobsEnabledCondition(n.GetName()), "Scheduler jobs were enabled")
+	}
+	return ptr.T(WaitingStatus(SyncStatusPending, "scheduler jobs enabling")), err
+}
+
+func (n *execNode) setJobsDrainingCondition(ctx context.Context, condition string, message string) {
+	n.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+		Type:    condition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Update",
+		Message: message,
+	})
+}
+
+func (n *execNode) getNodeAddresses(ctx context.Context) ([]string, error) {
+	podList := &corev1.PodList{}
+	if err := n.ytsaurus.APIProxy().ListObjects(ctx, podList, n.labeller.GetListOptions()...); err != nil {
+		return nil, fmt.Errorf("query pods: %w", err)
+	}
+
+	addresses := make([]string, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		addresses = append(addresses, net.JoinHostPort(n.cfgen.GetExecNodeHost(pod.Spec.Hostname), strconv.Itoa(consts.ExecNodeRPCPort)))
+	}
+	return addresses, nil
+}
+
+func (n *execNode) setSchedulerJobsDisabled(ctx context.Context, disabled bool) error {
+	addresses, err := n.getNodeAddresses(ctx)
+	if err != nil {
+		return err
+	}
+	return SetSchedulerJobsDisabled(ctx, n.yc.GetYtClient(), addresses, disabled)
+}
+
+func (n *execNode) getRunningJobs(ctx context.Context) (jobCount int, nodeCount int, err error) {
+	addresses, err := n.getNodeAddresses(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return GetRunningJobs(ctx, n.yc.GetYtClient(), addresses)
+}
+
+// SetSchedulerJobsDisabled disables or enables scheduler jobs on the cluster nodes.
+// Nodes which are not registered in Cypress are skipped.
+func SetSchedulerJobsDisabled(ctx context.Context, ytClient yt.Client, addresses []string, disabled bool) error {
+	for _, address := range addresses {
+		// Same as
+		//
+		// 	yt set "//sys/cluster_nodes/$node/@disable_scheduler_jobs" "%true"
+		//
+		err := ytClient.SetNode(ctx,
+			ypath.Path("//sys/cluster_nodes").Child(address).Attr("disable_scheduler_jobs"),
+			disabled,
+			&yt.SetNodeOptions{},
+		)
+		if err != nil && !yterrors.ContainsResolveError(err) {
+			return fmt.Errorf("set disable_scheduler_jobs on node %q: %w", address, err)
+		}
+	}
+	return nil
+}
+
+// GetRunningJobs returns the number of running jobs on the cluster nodes
+// and the number of nodes that still have jobs.
+func GetRunningJobs(ctx context.Context, ytClient yt.Client, addresses []string) (jobCount int, nodeCount int, err error) {
+	for _, address := range addresses {
+		var userSlots int
+		err := ytClient.GetNode(ctx,
+			ypath.Path("//sys/cluster_nodes").Child(address).Attr("resource_usage").Child("user_slots"),
+			&userSlots,
+			getReadOnlyGetOptions(),
+		)
+		if err != nil {
+			if yterrors.ContainsResolveError(err) {
+				continue
+			}
+			return 0, 0, fmt.Errorf("get running jobs on node %q: %w", address, err)
+		}
+		if userSlots > 0 {
+			jobCount += userSlots
+			nodeCount++
+		}
+	}
+	return jobCount, nodeCount, nil
+}
@@ -0,0 +1,151 @@
+package components
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	"go.ytsaurus.tech/yt/go/yterrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
+)
+
+var _ = Describe("Exec nodes draining test", func() {
+	var mockYtClient *mock_yt.MockClient
+	addresses := []string{"end-0.exec-nodes:9029", "end-1.exec-nodes:9029", "end-2.exec-nodes:9029"}
+
+	BeforeEach(func() {
+		mockYtClient = mock_yt.NewMockClient(ctrl)
+	})
+
+	It("Running jobs are counted per node", func() {
+		userSlots := map[string]int{
+			"//sys/cluster_nodes/end-0.exec-nodes:9029/@resource_usage/user_slots": 3,
+			"//sys/cluster_nodes/end-1.exec-nodes:9029/@resource_usage/user_slots": 0,
+		}
+		mockYtClient.EXPECT().
+			GetNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(len(addresses)).
+			DoAndReturn(func(_ context.Context, path ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				count, ok := userSlots[path.YPath().String()]
+				if !ok {
+					return yterrors.Err(yterrors.CodeResolveError, "node is not registered")
+				}
+				*result.(*int) = count
+				return nil
+			})
+
+		jobCount, nodeCount, err := GetRunningJobs(context.Background(), mockYtClient, addresses)
+		Expect(err).Should(Succeed())
+		Expect(jobCount).Should(Equal(3))
+		Expect(nodeCount).Should(Equal(1))
+	})
+
+	It("Scheduler jobs are disabled on each node", func() {
+		var disabledNodes []string
+		mockYtClient.EXPECT().
+			SetNode(gomock.Any(), gomock.Any(), gomock.Eq(true), gomock.Any()).
+			Times(len(addresses)).
+			DoAndReturn(func(_ context.Context, path ypath.YPath, _ interface{}, _ *yt.SetNodeOptions) error {
+				disabledNodes = append(disabledNodes, path.YPath().String())
+				return nil
+			})
+
+		Expect(SetSchedulerJobsDisabled(context.Background(), mockYtClient, addresses, true)).Should(Succeed())
+		Expect(disabledNodes).Should(ConsistOf(
+			"//sys/cluster_nodes/end-0.exec-nodes:9029/@disable_scheduler_jobs",
+			"//sys/cluster_nodes/end-1.exec-nodes:9029/@disable_scheduler_jobs",
+			"//sys/cluster_nodes/end-2.exec-nodes:9029/@disable_scheduler_jobs",
+		))
+	})
+
+	It("Update isn't finished until scheduler jobs are enabled", func() {
+		ctx := context.Background()
+		resource := &v1.Ytsaurus{
+			ObjectMeta: metav1.ObjectMeta{Name: "ytsaurus", Namespace: "default"},
+			Status: v1.YtsaurusStatus{
+				State: v1.ClusterStateUpdating,
+				UpdateStatus: v1.UpdateStatus{
+					State:      v1.UpdateStateWaitingForPodsCreation,
+					Components: []string{"ExecNode"},
+				},
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "end-0",
+				Namespace: "default",
+				Labels:    map[string]string{consts.YTComponentLabelName: "ytsaurus-" + consts.YTComponentLabelExecNode},
+			},
+			Spec: corev1.PodSpec{Hostname: "end-0"},
+		}
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource, pod).Build()
+		ytsaurus := apiproxy.NewYtsaurus(resource, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(resource, "cluster.local")
+
+		l := labeller.Labeller{
+			ObjectMeta:     &resource.ObjectMeta,
+			APIProxy:       ytsaurus.APIProxy(),
+			ComponentLabel: consts.YTComponentLabelExecNode,
+			ComponentName:  "ExecNode",
+		}
+		yc := NewFakeYtsaurusClient(mockYtClient)
+		n := &execNode{
+			componentBase: componentBase{
+				labeller:     &l,
+				ytsaurus:     ytsaurus,
+				cfgen:        cfgen,
+				dependencies: NewFakeDependencyChecker(NewFakeComponentWithLabel("master", consts.YTComponentLabelMaster), yc),
+			},
+			server:       NewFakeServer(),
+			drainTimeout: &metav1.Duration{},
+			yc:           yc,
+			rack:         newRackSetup(cfgen.GetExecNodeHost, ytsaurus, l, consts.ExecNodeRPCPort),
+		}
+		ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+			Type:   labeller.GetJobsDrainingStartedCondition(n.GetName()),
+			Status: metav1.ConditionTrue,
+			Reason: "Update",
+		})
+
+		// Pods creation step is done when all components are ready or updating, as in the component manager.
+		flow := NewUpdateFlow(UpdateStep{
+			State: v1.UpdateStateWaitingForPodsCreation,
+			IsDone: func() bool {
+				status := getStatus(n).SyncStatus
+				return status == SyncStatusReady || status == SyncStatusUpdating
+			},
+		})
+		result, err := flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(result).Should(BeNil())
+
+		mockYtClient.EXPECT().
+			SetNode(gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/cluster_nodes").Child(cfgen.GetExecNodeHost("end-0")+":9029").Attr("disable_scheduler_jobs")),
+				gomock.Eq(false),
+				gomock.Any()).
+			Return(nil)
+		Expect(n.Sync(ctx)).Should(Succeed())
+
+		_, err = flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetClusterState()).Should(Equal(v1.ClusterStateUpdateFinishing))
+	})
+})
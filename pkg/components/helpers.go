@@ -107,6 +107,9 @@ func CreateUser(ctx context.Context, ytClient yt.Client, userName, token string,
 }
 
 func IsUpdatingComponent(ytsaurus *apiproxy.Ytsaurus, component Component) bool {
+	if !IsManagedComponent(component) {
+		return false
+	}
 	componentNames := ytsaurus.GetLocalUpdatingComponents()
 	return (componentNames == nil && component.IsUpdatable()) || slices.Contains(componentNames, component.GetName())
 }
@@ -123,6 +126,9 @@ func handleUpdatingClusterState(
 
 	if IsUpdatingComponent(ytsaurus, cmp) {
 		if ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval {
+			if server.isRollingUpdate() {
+				return updatePodsRolling(ctx, server, cmpBase, dry)
+			}
 			if !dry {
 				err = removePods(ctx, server, cmpBase)
 			}
@@ -138,6 +144,28 @@ func handleUpdatingClusterState(
 	return nil, err
 }
 
+// getFullUpdateStatus returns the status of the component which requires a full update,
+// restart of the component alone is done by the local update. Masters don't use it,
+// their restart always goes through the full update.
+func getFullUpdateStatus(server server) ComponentStatus {
+	if server.isRestartOnlyUpdate() {
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, server.getUpdateReason())
+	}
+	return NewComponentStatus(SyncStatusNeedFullUpdate, server.getUpdateReason())
+}
+
+// getUpdateReason describes why the pods don't correspond to the spec.
+func getUpdateReason(imageCorrespondsToSpec bool, image string, configHelper *ConfigHelper) string {
+	if !imageCorrespondsToSpec {
+		return fmt.Sprintf("Image changed to %s", image)
+	}
+	fileNames, err := configHelper.GetChangedFileNames()
+	if err != nil || len(fileNames) == 0 {
+		return "Config changed"
+	}
+	return fmt.Sprintf("Config changed: %s", strings.Join(fileNames, ", "))
+}
+
 func SetPathAcl(path string, acl []yt.ACE) string {
 	formattedAcl, err := yson.MarshalFormat(acl, yson.FormatText)
 	if err != nil {
@@ -19,7 +19,6 @@ type httpProxy struct {
 	server server
 
 	serviceType      corev1.ServiceType
-	master           Component
 	balancingService *resources.HTTPService
 
 	role        string
@@ -31,7 +30,7 @@ type httpProxy struct {
 func NewHTTPProxy(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	masterReconciler Component,
+	dependencies DependencyChecker,
 	spec ytv1.HTTPProxiesSpec,
 ) Component {
 	resource := ytsaurus.GetResource()
@@ -79,12 +78,12 @@ func NewHTTPProxy(
 
 	return &httpProxy{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server:           srv,
-		master:           masterReconciler,
 		serviceType:      spec.ServiceType,
 		role:             spec.Role,
 		httpsSecret:      httpsSecret,
@@ -96,6 +95,14 @@ func (hp *httpProxy) IsUpdatable() bool {
 	return true
 }
 
+func (hp *httpProxy) IsManaged() bool {
+	return hp.server.isManaged()
+}
+
+func (hp *httpProxy) GetPodsStatus() PodsStatus {
+	return hp.server.getPodsStatus()
+}
+
 func (hp *httpProxy) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		hp.server,
@@ -106,18 +113,22 @@ func (hp *httpProxy) Fetch(ctx context.Context) error {
 func (hp *httpProxy) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !hp.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(hp.ytsaurus.GetClusterState()) && hp.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, hp.server.getUpdateReason()), err
 	}
 
-	if hp.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(hp.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, hp.ytsaurus, hp, &hp.componentBase, hp.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(hp.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, hp.master.GetName()), err
+	if status := hp.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if hp.server.needSync() {
@@ -148,13 +159,8 @@ func (hp *httpProxy) doSync(ctx context.Context, dry bool) (ComponentStatus, err
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (hp *httpProxy) Status(ctx context.Context) ComponentStatus {
-	status, err := hp.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (hp *httpProxy) Status(ctx context.Context) (ComponentStatus, error) {
+	return hp.doSync(ctx, true)
 }
 
 func (hp *httpProxy) Sync(ctx context.Context) error {
@@ -17,6 +17,7 @@ import (
 	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/resources"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
 )
@@ -165,12 +166,29 @@ func (j *InitJob) Sync(ctx context.Context, dry bool) (ComponentStatus, error) {
 		return WaitingStatus(SyncStatusPending, fmt.Sprintf("%s creation", j.initJob.Name())), err
 	}
 
+	if !dry {
+		metrics.ReportInitJobFailures(
+			j.labeller.ObjectMeta.Namespace,
+			j.labeller.ObjectMeta.Name,
+			j.labeller.ComponentName,
+			j.initJob.Name(),
+			j.initJob.Failed())
+	}
+
 	if !j.initJob.Completed() {
 		logger.Info("Init job is not completed for " + j.labeller.ComponentName)
 		return WaitingStatus(SyncStatusBlocked, fmt.Sprintf("%s completion", j.initJob.Name())), err
 	}
 
 	if !dry {
+		if duration, ok := j.initJob.Duration(); ok {
+			metrics.ObserveInitJobDuration(
+				j.labeller.ObjectMeta.Namespace,
+				j.labeller.ObjectMeta.Name,
+				j.labeller.ComponentName,
+				j.initJob.Name(),
+				duration)
+		}
 		j.conditionsManager.SetStatusCondition(metav1.Condition{
 			Type:    j.initCompletedCondition,
 			Status:  metav1.ConditionTrue,
@@ -57,6 +57,7 @@ func NewMaster(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus) Component
 		cfgen.GetMastersStatefulSetName(),
 		cfgen.GetMastersServiceName(),
 		cfgen.GetMasterConfig,
+		withQuorumDisruptionBudget(),
 	)
 
 	initJob := NewInitJob(
@@ -98,6 +99,14 @@ func (m *master) IsUpdatable() bool {
 	return true
 }
 
+func (m *master) IsManaged() bool {
+	return m.server.isManaged()
+}
+
+func (m *master) GetPodsStatus() PodsStatus {
+	return m.server.getPodsStatus()
+}
+
 func (m *master) Fetch(ctx context.Context) error {
 	if m.ytsaurus.GetResource().Spec.AdminCredentials != nil {
 		err := m.ytsaurus.APIProxy().FetchObject(
@@ -289,11 +298,17 @@ func (m *master) createExitReadOnlyScript() string {
 func (m *master) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !m.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(m.ytsaurus.GetClusterState()) && m.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedFullUpdate), err
+		// Local update removes all pods at once, so even the restart of masters is done by the full update
+		// which keeps the masters read-only meanwhile.
+		return NewComponentStatus(SyncStatusNeedFullUpdate, m.server.getUpdateReason()), err
 	}
 
-	if m.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(m.ytsaurus.GetClusterState()) {
 		if m.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForMasterExitReadOnly {
 			st, err := m.exitReadOnly(ctx, dry)
 			return *st, err
@@ -321,13 +336,8 @@ func (m *master) doSync(ctx context.Context, dry bool) (ComponentStatus, error)
 	return m.initJob.Sync(ctx, dry)
 }
 
-func (m *master) Status(ctx context.Context) ComponentStatus {
-	status, err := m.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (m *master) Status(ctx context.Context) (ComponentStatus, error) {
+	return m.doSync(ctx, true)
 }
 
 func (m *master) Sync(ctx context.Context) error {
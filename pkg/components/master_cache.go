@@ -72,6 +72,14 @@ func (m *masterCache) IsUpdatable() bool {
 	return true
 }
 
+func (m *masterCache) IsManaged() bool {
+	return m.server.isManaged()
+}
+
+func (m *masterCache) GetPodsStatus() PodsStatus {
+	return m.server.getPodsStatus()
+}
+
 func (m *masterCache) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		m.server,
@@ -116,11 +124,15 @@ func (m *masterCache) initMedia() string {
 func (m *masterCache) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !m.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(m.ytsaurus.GetClusterState()) && m.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedFullUpdate), err
+		return getFullUpdateStatus(m.server), err
 	}
 
-	if m.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(m.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, m.ytsaurus, m, &m.componentBase, m.server, dry); status != nil {
 			return *status, err
 		}
@@ -143,13 +155,8 @@ func (m *masterCache) doSync(ctx context.Context, dry bool) (ComponentStatus, er
 	return m.initJob.Sync(ctx, dry)
 }
 
-func (m *masterCache) Status(ctx context.Context) ComponentStatus {
-	status, err := m.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (m *masterCache) Status(ctx context.Context) (ComponentStatus, error) {
+	return m.doSync(ctx, true)
 }
 
 func (m *masterCache) Sync(ctx context.Context) error {
@@ -27,6 +27,7 @@ type microservice interface {
 	podsManager
 	needSync() bool
 	needUpdate() bool
+	getUpdateReason() string
 	getImage() string
 	getHTTPService() *resources.HTTPService
 	buildDeployment() *appsv1.Deployment
@@ -115,7 +116,7 @@ func (m *microserviceImpl) needSync() bool {
 		needReload = false
 	}
 	return m.configHelper.NeedInit() ||
-		(m.ytsaurus.GetClusterState() == v1.ClusterStateUpdating && needReload) ||
+		(v1.IsUpdatingClusterState(m.ytsaurus.GetClusterState()) && needReload) ||
 		!resources.Exists(m.service) ||
 		m.deployment.NeedSync(m.instanceCount)
 }
@@ -197,6 +198,10 @@ func (m *microserviceImpl) needUpdate() bool {
 	return needReload
 }
 
+func (m *microserviceImpl) getUpdateReason() string {
+	return getUpdateReason(m.podsImageCorrespondsToSpec(), m.image, m.configHelper)
+}
+
 func (m *microserviceImpl) exists() bool {
 	return resources.Exists(m.deployment) &&
 		resources.Exists(m.service)
@@ -213,6 +218,18 @@ func (m *microserviceImpl) removePods(ctx context.Context) error {
 	return m.Sync(ctx)
 }
 
+func (m *microserviceImpl) getPodsStatus() PodsStatus {
+	status := PodsStatus{}
+	if resources.Exists(m.deployment) {
+		status = getPodTemplateStatus(&m.deployment.OldObject().(*appsv1.Deployment).Spec.Template)
+		status.ReadyReplicas = m.deployment.GetReadyReplicas()
+		status.UpdatedReplicas = m.deployment.GetUpdatedReplicas()
+	}
+	status.DesiredImage = m.image
+	status.DesiredReplicas = m.instanceCount
+	return status
+}
+
 func (m *microserviceImpl) getImage() string {
 	return m.image
 }
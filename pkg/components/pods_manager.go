@@ -2,9 +2,14 @@ package components
 
 import (
 	"context"
+	"fmt"
 
+	"go.ytsaurus.tech/library/go/ptr"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
 )
 
@@ -14,6 +19,33 @@ type podsManager interface {
 	arePodsRemoved(ctx context.Context) bool
 	arePodsReady(ctx context.Context) bool
 	podsImageCorrespondsToSpec() bool
+	getPodsStatus() PodsStatus
+}
+
+// PodsStatus describes the desired and the observed state of the pods of the component.
+type PodsStatus struct {
+	DesiredImage    string
+	ObservedImage   string
+	DesiredReplicas int32
+	ReadyReplicas   int32
+	UpdatedReplicas int32
+	// ConfigHash is the hash of the configs the pods are running with.
+	ConfigHash string
+}
+
+// PodsStatusProvider is implemented by components which run their own pods.
+type PodsStatusProvider interface {
+	GetPodsStatus() PodsStatus
+}
+
+func getPodTemplateStatus(template *corev1.PodTemplateSpec) PodsStatus {
+	status := PodsStatus{
+		ConfigHash: template.Annotations[consts.ConfigHashAnnotationName],
+	}
+	if len(template.Spec.Containers) != 0 {
+		status.ObservedImage = template.Spec.Containers[0].Image
+	}
+	return status
 }
 
 func removePods(ctx context.Context, manager podsManager, c *componentBase) error {
@@ -34,6 +66,32 @@ func removePods(ctx context.Context, manager podsManager, c *componentBase) erro
 	return nil
 }
 
+// updatePodsRolling tracks the rolling update of server pods.
+// Nil status means that the next step of the rolling update
+// should be applied by the regular sync of the component.
+func updatePodsRolling(ctx context.Context, server server, c *componentBase, dry bool) (*ComponentStatus, error) {
+	if isPodsUpdated(c) {
+		return ptr.T(WaitingStatus(SyncStatusUpdating, "pods rolling update")), nil
+	}
+
+	if server.arePodsUpdated() {
+		if !dry {
+			setPodsUpdatedCondition(ctx, c, metav1.ConditionTrue, "Pods updated")
+		}
+		return ptr.T(WaitingStatus(SyncStatusUpdating, "pods rolling update")), nil
+	}
+
+	if server.needSync() {
+		return nil, nil
+	}
+
+	if !dry {
+		updated, total := server.getRollingUpdateProgress()
+		setPodsUpdatedCondition(ctx, c, metav1.ConditionFalse, fmt.Sprintf("Updated %d of %d pods", updated, total))
+	}
+	return ptr.T(WaitingStatus(SyncStatusUpdating, "pods rolling update")), nil
+}
+
 func isPodsRemovingStarted(c *componentBase) bool {
 	return c.ytsaurus.IsUpdateStatusConditionTrue(c.labeller.GetPodsRemovingStartedCondition())
 }
@@ -55,3 +113,16 @@ func setPodsRemovedCondition(ctx context.Context, c *componentBase) {
 		Message: "Pods removed",
 	})
 }
+
+func isPodsUpdated(c *componentBase) bool {
+	return c.ytsaurus.IsUpdateStatusConditionTrue(labeller.GetPodsUpdatedCondition(c.GetName()))
+}
+
+func setPodsUpdatedCondition(ctx context.Context, c *componentBase, status metav1.ConditionStatus, message string) {
+	c.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+		Type:    labeller.GetPodsUpdatedCondition(c.GetName()),
+		Status:  status,
+		Reason:  "Update",
+		Message: message,
+	})
+}
@@ -0,0 +1,317 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.ytsaurus.tech/yt/go/ypath"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+)
+
+type possibilityCheckResult struct {
+	Possible bool
+	Message  string
+}
+
+// possibilityCheck is a single pre-update check, its result is reported in a separate update condition.
+type possibilityCheck struct {
+	name  string
+	check func(ctx context.Context) (possibilityCheckResult, error)
+}
+
+func possible(message string) possibilityCheckResult {
+	return possibilityCheckResult{Possible: true, Message: message}
+}
+
+func impossible(message string) possibilityCheckResult {
+	return possibilityCheckResult{Possible: false, Message: message}
+}
+
+func GetPossibilityCheckCondition(name string) string {
+	return fmt.Sprintf("PossibilityCheck%s", name)
+}
+
+func getUpdatePossibilityChecksSpec(resource *ytv1.Ytsaurus) ytv1.UpdatePossibilityChecksSpec {
+	if resource.Spec.UpdatePossibilityChecks != nil {
+		return *resource.Spec.UpdatePossibilityChecks
+	}
+	return ytv1.UpdatePossibilityChecksSpec{
+		TabletCellBundlesHealth: true,
+		LostVitalChunks:         true,
+		QuorumMissingChunks:     true,
+		MastersHydra:            true,
+	}
+}
+
+func (yc *ytsaurusClient) getPossibilityChecks() []possibilityCheck {
+	spec := getUpdatePossibilityChecksSpec(yc.ytsaurus.GetResource())
+
+	var checks []possibilityCheck
+	if spec.TabletCellBundlesHealth {
+		checks = append(checks, possibilityCheck{
+			name: "TabletCellBundlesHealth",
+			check: func(ctx context.Context) (possibilityCheckResult, error) {
+				return yc.checkTabletCellBundlesHealth(ctx, spec.AllowedDegradedBundles)
+			},
+		})
+	}
+	if spec.LostVitalChunks {
+		checks = append(checks, possibilityCheck{
+			name: "LostVitalChunks",
+			check: func(ctx context.Context) (possibilityCheckResult, error) {
+				return yc.checkChunkCount(ctx, "//sys/lost_vital_chunks/@count", "lost vital chunks", 0)
+			},
+		})
+	}
+	if spec.QuorumMissingChunks {
+		checks = append(checks, possibilityCheck{
+			name: "QuorumMissingChunks",
+			check: func(ctx context.Context) (possibilityCheckResult, error) {
+				return yc.checkChunkCount(ctx, "//sys/quorum_missing_chunks/@count", "quorum missing chunks", 0)
+			},
+		})
+	}
+	if spec.MaxDataMissingChunks != nil {
+		checks = append(checks, possibilityCheck{
+			name: "DataMissingChunks",
+			check: func(ctx context.Context) (possibilityCheckResult, error) {
+				return yc.checkChunkCount(ctx, "//sys/data_missing_chunks/@count", "data missing chunks", *spec.MaxDataMissingChunks)
+			},
+		})
+	}
+	if spec.MastersHydra {
+		checks = append(checks, possibilityCheck{
+			name:  "MastersHydra",
+			check: yc.checkMastersHydra,
+		})
+	}
+	if spec.NoMasterAlerts {
+		checks = append(checks, possibilityCheck{
+			name:  "MasterAlerts",
+			check: yc.checkMasterAlerts,
+		})
+	}
+	for _, assertion := range spec.CypressAssertions {
+		assertion := assertion
+		checks = append(checks, possibilityCheck{
+			name: fmt.Sprintf("Cypress%s", assertion.Name),
+			check: func(ctx context.Context) (possibilityCheckResult, error) {
+				return yc.checkCypressAssertion(ctx, assertion)
+			},
+		})
+	}
+	return checks
+}
+
+// CheckHealth runs the health checks of the running cluster and returns the messages of the failed ones.
+// The running cluster is checked by the same checks which are enabled before updates.
+func (yc *ytsaurusClient) CheckHealth(ctx context.Context) ([]string, error) {
+	if yc.ytClient == nil {
+		return nil, fmt.Errorf("yt client is not initialized")
+	}
+
+	var failedChecks []string
+	for _, check := range yc.getPossibilityChecks() {
+		result, err := check.check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Possible {
+			failedChecks = append(failedChecks, result.Message)
+		}
+	}
+	return failedChecks, nil
+}
+
+// runPossibilityChecks runs all enabled checks and reports the result of each one in a separate condition.
+// Errors of YT requests interrupt the checks, so they are retried on the next reconciliation.
+func (yc *ytsaurusClient) runPossibilityChecks(ctx context.Context) error {
+	var failedChecks []string
+	for _, check := range yc.getPossibilityChecks() {
+		result, err := check.check(ctx)
+		if err != nil {
+			return err
+		}
+
+		status := metav1.ConditionTrue
+		if !result.Possible {
+			status = metav1.ConditionFalse
+			failedChecks = append(failedChecks, result.Message)
+		}
+		yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+			Type:    GetPossibilityCheckCondition(check.name),
+			Status:  status,
+			Reason:  "Update",
+			Message: result.Message,
+		})
+	}
+
+	if len(failedChecks) > 0 {
+		yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+			Type:    consts.ConditionNoPossibility,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Update",
+			Message: strings.Join(failedChecks, "; "),
+		})
+		return nil
+	}
+
+	yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
+		Type:    consts.ConditionHasPossibility,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Update",
+		Message: "Update is possible",
+	})
+	return nil
+}
+
+func (yc *ytsaurusClient) checkTabletCellBundlesHealth(ctx context.Context, allowedDegradedBundles []string) (possibilityCheckResult, error) {
+	notGoodBundles, err := GetNotGoodTabletCellBundles(ctx, yc.ytClient)
+	if err != nil {
+		return possibilityCheckResult{}, err
+	}
+
+	var degradedBundles []string
+	for _, bundle := range notGoodBundles {
+		if !slices.Contains(allowedDegradedBundles, bundle) {
+			degradedBundles = append(degradedBundles, bundle)
+		}
+	}
+
+	if len(degradedBundles) > 0 {
+		return impossible(fmt.Sprintf("Tablet cell bundles (%v) aren't in 'good' health", degradedBundles)), nil
+	}
+	return possible("Tablet cell bundles are in 'good' health"), nil
+}
+
+func (yc *ytsaurusClient) checkChunkCount(ctx context.Context, path, description string, maxCount int) (possibilityCheckResult, error) {
+	count := 0
+	if err := yc.ytClient.GetNode(ctx, ypath.Path(path), &count, nil); err != nil {
+		return possibilityCheckResult{}, err
+	}
+
+	if count > maxCount {
+		return impossible(fmt.Sprintf("There are %s: %v", description, count)), nil
+	}
+	return possible(fmt.Sprintf("Count of %s is %v", description, count)), nil
+}
+
+func (yc *ytsaurusClient) checkMasterCell(ctx context.Context, msgContext string, addrsPath ypath.Path) (possibilityCheckResult, error) {
+	addrs := make([]string, 0)
+	if err := yc.ytClient.ListNode(ctx, addrsPath, &addrs, nil); err != nil {
+		return possibilityCheckResult{}, err
+	}
+
+	leadingCount := 0
+	followingCount := 0
+
+	for _, addr := range addrs {
+		var hydra MasterHydra
+		err := yc.ytClient.GetNode(
+			ctx,
+			ypath.Path(addrsPath.JoinChild(addr, "orchid", "monitoring", "hydra")),
+			&hydra,
+			nil)
+		if err != nil {
+			return possibilityCheckResult{}, err
+		}
+
+		if !hydra.Active {
+			return impossible(fmt.Sprintf("There is a non-active %s: %v", msgContext, addrs)), nil
+		}
+
+		switch hydra.State {
+		case MasterStateLeading:
+			leadingCount += 1
+		case MasterStateFollowing:
+			followingCount += 1
+		}
+	}
+
+	if !(leadingCount == 1 && followingCount+1 == len(addrs)) {
+		return impossible(fmt.Sprintf("There is no %s leader or some peer is not active", msgContext)), nil
+	}
+
+	return possible("Update is possible"), nil
+}
+
+func (yc *ytsaurusClient) checkMastersHydra(ctx context.Context) (possibilityCheckResult, error) {
+	result, err := yc.checkMasterCell(ctx, "primary master", ypath.Path(`//sys/primary_masters`))
+	if err != nil || !result.Possible {
+		return result, err
+	}
+
+	if len(yc.ytsaurus.GetResource().Spec.SecondaryMasters) > 0 {
+		var (
+			cellTags         = make([]string, 0)
+			secondaryMasters = ypath.Path(`//sys/secondary_masters`)
+		)
+		if err := yc.ytClient.ListNode(ctx, secondaryMasters, &cellTags, nil); err != nil {
+			return possibilityCheckResult{}, err
+		}
+		for _, cellTag := range cellTags {
+			result, err := yc.checkMasterCell(
+				ctx,
+				fmt.Sprintf("secondary master (cell_tag: %q)", cellTag),
+				secondaryMasters.Child(cellTag),
+			)
+			if err != nil || !result.Possible {
+				return result, err
+			}
+		}
+	}
+
+	return possible("All masters are active and have leaders"), nil
+}
+
+func (yc *ytsaurusClient) checkMasterAlerts(ctx context.Context) (possibilityCheckResult, error) {
+	var alerts []interface{}
+	if err := yc.ytClient.GetNode(ctx, ypath.Path("//sys/@master_alerts"), &alerts, nil); err != nil {
+		return possibilityCheckResult{}, err
+	}
+
+	if len(alerts) > 0 {
+		return impossible(fmt.Sprintf("There are master alerts: %v", len(alerts))), nil
+	}
+	return possible("There are no master alerts"), nil
+}
+
+// normalizeJSONValue converts the value to the form produced by JSON decoding,
+// so values obtained from YSON and from JSON can be compared.
+func normalizeJSONValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func (yc *ytsaurusClient) checkCypressAssertion(ctx context.Context, assertion ytv1.CypressAssertionSpec) (possibilityCheckResult, error) {
+	var expected interface{}
+	if err := json.Unmarshal([]byte(assertion.Value), &expected); err != nil {
+		return impossible(fmt.Sprintf("Expected value of %s is not a valid JSON: %v", assertion.Path, err)), nil
+	}
+
+	var actual interface{}
+	if err := yc.ytClient.GetNode(ctx, ypath.Path(assertion.Path), &actual, nil); err != nil {
+		return possibilityCheckResult{}, err
+	}
+	actual, err := normalizeJSONValue(actual)
+	if err != nil {
+		return possibilityCheckResult{}, err
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		return impossible(fmt.Sprintf("Value of %s is %v, expected %v", assertion.Path, actual, expected)), nil
+	}
+	return possible(fmt.Sprintf("Value of %s is %v", assertion.Path, actual)), nil
+}
@@ -0,0 +1,139 @@
+package components
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ptr "k8s.io/utils/pointer"
+
+	v1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+)
+
+var _ = Describe("Update possibility checks test", func() {
+	var mockYtClient *mock_yt.MockClient
+	var ytsaurusSpec *v1.Ytsaurus
+
+	BeforeEach(func() {
+		mockYtClient = mock_yt.NewMockClient(ctrl)
+		ytsaurusSpec = &v1.Ytsaurus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ytsaurus",
+				Namespace: "default",
+			},
+			Spec: v1.YtsaurusSpec{
+				EnableFullUpdate: true,
+				UpdatePossibilityChecks: &v1.UpdatePossibilityChecksSpec{
+					MaxDataMissingChunks: ptr.Int(1),
+					CypressAssertions: []v1.CypressAssertionSpec{
+						{
+							Name:  "ChunkReplicator",
+							Path:  "//sys/@chunk_replicator_enabled",
+							Value: "true",
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("Each check is reported separately", func() {
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, nil, record.NewFakeRecorder(10), nil)
+		yc := &ytsaurusClient{
+			componentBase: componentBase{ytsaurus: ytsaurus},
+			ytClient:      mockYtClient,
+		}
+
+		mockYtClient.EXPECT().
+			GetNode(
+				gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/data_missing_chunks/@count")),
+				gomock.Any(),
+				gomock.Nil()).
+			DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				*result.(*int) = 2
+				return nil
+			})
+		mockYtClient.EXPECT().
+			GetNode(
+				gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/@chunk_replicator_enabled")),
+				gomock.Any(),
+				gomock.Nil()).
+			DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				*result.(*interface{}) = true
+				return nil
+			})
+
+		Expect(yc.runPossibilityChecks(context.Background())).Should(Succeed())
+
+		Expect(ytsaurus.IsUpdateStatusConditionTrue(GetPossibilityCheckCondition("DataMissingChunks"))).Should(BeFalse())
+		Expect(ytsaurus.IsUpdateStatusConditionTrue(GetPossibilityCheckCondition("CypressChunkReplicator"))).Should(BeTrue())
+		Expect(ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNoPossibility)).Should(BeTrue())
+		Expect(ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionHasPossibility)).Should(BeFalse())
+	})
+
+	It("Health checks report failed checks", func() {
+		// The default checks don't include master alerts.
+		ytsaurusSpec.Spec.UpdatePossibilityChecks = nil
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, nil, record.NewFakeRecorder(10), nil)
+		yc := &ytsaurusClient{
+			componentBase: componentBase{ytsaurus: ytsaurus},
+			ytClient:      mockYtClient,
+		}
+
+		mockYtClient.EXPECT().
+			ListNode(
+				gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/tablet_cell_bundles")),
+				gomock.Any(),
+				gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.ListNodeOptions) error {
+				*result.(*[]TabletCellBundleHealth) = []TabletCellBundleHealth{
+					{Name: "default", Health: "good"},
+					{Name: "sys", Health: "failed"},
+				}
+				return nil
+			})
+		mockYtClient.EXPECT().
+			GetNode(
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Nil()).
+			DoAndReturn(func(_ context.Context, path ypath.YPath, result interface{}, _ *yt.GetNodeOptions) error {
+				switch path {
+				case ypath.Path("//sys/@master_alerts"):
+					*result.(*[]interface{}) = []interface{}{"alert"}
+				case ypath.Path("//sys/primary_masters/m-0/orchid/monitoring/hydra"):
+					*result.(*MasterHydra) = MasterHydra{Active: true, State: MasterStateLeading}
+				}
+				return nil
+			}).
+			Times(3)
+		mockYtClient.EXPECT().
+			ListNode(
+				gomock.Any(),
+				gomock.Eq(ypath.Path("//sys/primary_masters")),
+				gomock.Any(),
+				gomock.Nil()).
+			DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.ListNodeOptions) error {
+				*result.(*[]string) = []string{"m-0"}
+				return nil
+			})
+
+		failedChecks, err := yc.CheckHealth(context.Background())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(failedChecks).Should(Equal([]string{
+			"Tablet cell bundles ([sys]) aren't in 'good' health",
+		}))
+	})
+})
@@ -25,7 +25,6 @@ type queryTracker struct {
 	server server
 
 	ytsaurusClient YtsaurusClient
-	tabletNodes    []Component
 	initCondition  string
 	initQTState    *InitJob
 	secret         *resources.StringSecret
@@ -35,7 +34,7 @@ func NewQueryTracker(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
 	yc YtsaurusClient,
-	tabletNodes []Component,
+	dependencies DependencyChecker,
 ) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
@@ -66,12 +65,12 @@ func NewQueryTracker(
 
 	return &queryTracker{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server:         server,
-		tabletNodes:    tabletNodes,
 		initCondition:  "queryTrackerInitCompleted",
 		ytsaurusClient: yc,
 		initQTState: NewInitJob(
@@ -95,6 +94,14 @@ func (qt *queryTracker) IsUpdatable() bool {
 	return true
 }
 
+func (qt *queryTracker) IsManaged() bool {
+	return qt.server.isManaged()
+}
+
+func (qt *queryTracker) GetPodsStatus() PodsStatus {
+	return qt.server.getPodsStatus()
+}
+
 func (qt *queryTracker) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		qt.server,
@@ -106,25 +113,34 @@ func (qt *queryTracker) Fetch(ctx context.Context) error {
 func (qt *queryTracker) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !qt.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(qt.ytsaurus.GetClusterState()) && qt.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, qt.server.getUpdateReason()), err
 	}
 
-	if qt.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(qt.ytsaurus.GetClusterState()) {
 		if IsUpdatingComponent(qt.ytsaurus, qt) {
 			if qt.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval && IsUpdatingComponent(qt.ytsaurus, qt) {
-				if !dry {
-					err = removePods(ctx, qt.server, &qt.componentBase)
+				if !qt.server.isRollingUpdate() {
+					if !dry {
+						err = removePods(ctx, qt.server, &qt.componentBase)
+					}
+					return WaitingStatus(SyncStatusUpdating, "pods removal"), err
+				}
+				if status, err := updatePodsRolling(ctx, qt.server, &qt.componentBase, dry); status != nil {
+					return *status, err
+				}
+			} else {
+				if status, err := qt.updateQTState(ctx, dry); status != nil {
+					return *status, err
+				}
+				if qt.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForPodsCreation &&
+					qt.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForQTStateUpdate {
+					return NewComponentStatus(SyncStatusReady, "Nothing to do now"), err
 				}
-				return WaitingStatus(SyncStatusUpdating, "pods removal"), err
-			}
-
-			if status, err := qt.updateQTState(ctx, dry); status != nil {
-				return *status, err
-			}
-			if qt.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForPodsCreation &&
-				qt.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForQTStateUpdate {
-				return NewComponentStatus(SyncStatusReady, "Nothing to do now"), err
 			}
 		} else {
 			return NewComponentStatus(SyncStatusReady, "Not updating component"), err
@@ -155,20 +171,18 @@ func (qt *queryTracker) doSync(ctx context.Context, dry bool) (ComponentStatus,
 	}
 
 	// Wait for tablet nodes to proceed with query tracker state init.
-	if qt.tabletNodes == nil || len(qt.tabletNodes) == 0 {
+	if len(qt.ytsaurus.GetResource().Spec.TabletNodes) == 0 {
 		return WaitingStatus(SyncStatusBlocked, "tablet nodes"), fmt.Errorf("cannot initialize query tracker without tablet nodes")
 	}
 
-	for _, tnd := range qt.tabletNodes {
-		if !IsRunningStatus(tnd.Status(ctx).SyncStatus) {
-			return WaitingStatus(SyncStatusBlocked, "tablet nodes"), err
-		}
+	if status := qt.waitForDependencies(ctx, consts.YTComponentLabelTabletNode); status != nil {
+		return *status, err
 	}
 
 	var ytClient yt.Client
-	if qt.ytsaurus.GetClusterState() != ytv1.ClusterStateUpdating {
-		if qt.ytsaurusClient.Status(ctx).SyncStatus != SyncStatusReady {
-			return WaitingStatus(SyncStatusBlocked, qt.ytsaurusClient.GetName()), err
+	if !ytv1.IsUpdatingClusterState(qt.ytsaurus.GetClusterState()) {
+		if status := qt.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+			return *status, err
 		}
 
 		if !dry {
@@ -189,7 +203,7 @@ func (qt *queryTracker) doSync(ctx context.Context, dry bool) (ComponentStatus,
 		return status, err
 	}
 
-	if qt.ytsaurus.GetClusterState() != ytv1.ClusterStateUpdating {
+	if !ytv1.IsUpdatingClusterState(qt.ytsaurus.GetClusterState()) {
 		if !dry {
 			err = qt.init(ctx, ytClient)
 			if err != nil {
@@ -324,13 +338,8 @@ func (qt *queryTracker) init(ctx context.Context, ytClient yt.Client) (err error
 	return
 }
 
-func (qt *queryTracker) Status(ctx context.Context) ComponentStatus {
-	status, err := qt.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (qt *queryTracker) Status(ctx context.Context) (ComponentStatus, error) {
+	return qt.doSync(ctx, true)
 }
 
 func (qt *queryTracker) Sync(ctx context.Context) error {
@@ -377,6 +386,26 @@ func (qt *queryTracker) updateQTState(ctx context.Context, dry bool) (*Component
 	}
 }
 
+// GetUpdateSteps returns the steps of the query tracker state update, they are skipped if the query tracker isn't updating.
+func (qt *queryTracker) GetUpdateSteps() []UpdateStep {
+	return []UpdateStep{
+		{
+			State:     ytv1.UpdateStateWaitingForQTStateUpdatingPrepare,
+			Message:   "Waiting for query tracker state prepare for updating",
+			ShouldRun: func() bool { return IsUpdatingComponent(qt.ytsaurus, qt) },
+			IsDone: func() bool {
+				return qt.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionQTStatePreparedForUpdating)
+			},
+		},
+		{
+			State:     ytv1.UpdateStateWaitingForQTStateUpdate,
+			Message:   "Waiting for query tracker state updating to finish",
+			ShouldRun: func() bool { return IsUpdatingComponent(qt.ytsaurus, qt) },
+			IsDone:    func() bool { return qt.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionQTStateUpdated) },
+		},
+	}
+}
+
 func (qt *queryTracker) setConditionQTStatePreparedForUpdating(ctx context.Context) {
 	qt.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
 		Type:    consts.ConditionQTStatePreparedForUpdating,
@@ -24,8 +24,6 @@ type queueAgent struct {
 	server server
 
 	ytsaurusClient YtsaurusClient
-	master         Component
-	tabletNodes    []Component
 	initCondition  string
 	initQAState    *InitJob
 	secret         *resources.StringSecret
@@ -35,8 +33,7 @@ func NewQueueAgent(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
 	yc YtsaurusClient,
-	master Component,
-	tabletNodes []Component,
+	dependencies DependencyChecker,
 ) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
@@ -67,13 +64,12 @@ func NewQueueAgent(
 
 	return &queueAgent{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server:         server,
-		master:         master,
-		tabletNodes:    tabletNodes,
 		initCondition:  "queueAgentInitCompleted",
 		ytsaurusClient: yc,
 		initQAState: NewInitJob(
@@ -97,6 +93,14 @@ func (qa *queueAgent) IsUpdatable() bool {
 	return true
 }
 
+func (qa *queueAgent) IsManaged() bool {
+	return qa.server.isManaged()
+}
+
+func (qa *queueAgent) GetPodsStatus() PodsStatus {
+	return qa.server.getPodsStatus()
+}
+
 func (qa *queueAgent) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		qa.server,
@@ -108,28 +112,26 @@ func (qa *queueAgent) Fetch(ctx context.Context) error {
 func (qa *queueAgent) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !qa.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(qa.ytsaurus.GetClusterState()) && qa.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, qa.server.getUpdateReason()), err
 	}
 
-	if qa.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(qa.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, qa.ytsaurus, qa, &qa.componentBase, qa.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(qa.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, qa.master.GetName()), err
-	}
-
 	// It makes no sense to start queue agents without tablet nodes.
-	if qa.tabletNodes == nil || len(qa.tabletNodes) == 0 {
+	if len(qa.ytsaurus.GetResource().Spec.TabletNodes) == 0 {
 		return WaitingStatus(SyncStatusBlocked, "tablet nodes"), fmt.Errorf("cannot initialize queue agent without tablet nodes")
 	}
-	for _, tnd := range qa.tabletNodes {
-		if !IsRunningStatus(tnd.Status(ctx).SyncStatus) {
-			return WaitingStatus(SyncStatusBlocked, tnd.GetName()), err
-		}
+	if status := qa.waitForDependencies(ctx, consts.YTComponentLabelMaster, consts.YTComponentLabelTabletNode); status != nil {
+		return *status, err
 	}
 
 	if qa.secret.NeedSync(consts.TokenSecretKey, "") {
@@ -156,9 +158,9 @@ func (qa *queueAgent) doSync(ctx context.Context, dry bool) (ComponentStatus, er
 	}
 
 	var ytClient yt.Client
-	if qa.ytsaurus.GetClusterState() != ytv1.ClusterStateUpdating {
-		if qa.ytsaurusClient.Status(ctx).SyncStatus != SyncStatusReady {
-			return WaitingStatus(SyncStatusBlocked, qa.ytsaurusClient.GetName()), err
+	if !ytv1.IsUpdatingClusterState(qa.ytsaurus.GetClusterState()) {
+		if status := qa.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+			return *status, err
 		}
 
 		if !dry {
@@ -298,13 +300,8 @@ func (qa *queueAgent) prepareInitQueueAgentState() {
 	container.EnvFrom = []corev1.EnvFromSource{qa.secret.GetEnvSource()}
 }
 
-func (qa *queueAgent) Status(ctx context.Context) ComponentStatus {
-	status, err := qa.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (qa *queueAgent) Status(ctx context.Context) (ComponentStatus, error) {
+	return qa.doSync(ctx, true)
 }
 
 func (qa *queueAgent) Sync(ctx context.Context) error {
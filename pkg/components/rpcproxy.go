@@ -16,8 +16,6 @@ type rpcProxy struct {
 	componentBase
 	server server
 
-	master Component
-
 	serviceType      *v1.ServiceType
 	balancingService *resources.RPCService
 	tlsSecret        *resources.TLSSecret
@@ -26,7 +24,7 @@ type rpcProxy struct {
 func NewRPCProxy(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	masterReconciler Component,
+	dependencies DependencyChecker,
 	spec ytv1.RPCProxiesSpec) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
@@ -70,12 +68,12 @@ func NewRPCProxy(
 
 	return &rpcProxy{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server:           server,
-		master:           masterReconciler,
 		serviceType:      spec.ServiceType,
 		balancingService: balancingService,
 		tlsSecret:        tlsSecret,
@@ -86,6 +84,14 @@ func (rp *rpcProxy) IsUpdatable() bool {
 	return true
 }
 
+func (rp *rpcProxy) IsManaged() bool {
+	return rp.server.isManaged()
+}
+
+func (rp *rpcProxy) GetPodsStatus() PodsStatus {
+	return rp.server.getPodsStatus()
+}
+
 func (rp *rpcProxy) Fetch(ctx context.Context) error {
 	fetchable := []resources.Fetchable{
 		rp.server,
@@ -99,18 +105,22 @@ func (rp *rpcProxy) Fetch(ctx context.Context) error {
 func (rp *rpcProxy) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !rp.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(rp.ytsaurus.GetClusterState()) && rp.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, rp.server.getUpdateReason()), err
 	}
 
-	if rp.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(rp.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, rp.ytsaurus, rp, &rp.componentBase, rp.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(rp.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, rp.master.GetName()), err
+	if status := rp.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if rp.server.needSync() {
@@ -141,13 +151,8 @@ func (rp *rpcProxy) doSync(ctx context.Context, dry bool) (ComponentStatus, erro
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (rp *rpcProxy) Status(ctx context.Context) ComponentStatus {
-	status, err := rp.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (rp *rpcProxy) Status(ctx context.Context) (ComponentStatus, error) {
+	return rp.doSync(ctx, true)
 }
 
 func (rp *rpcProxy) Sync(ctx context.Context) error {
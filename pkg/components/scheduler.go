@@ -22,9 +22,6 @@ import (
 type scheduler struct {
 	componentBase
 	server        server
-	master        Component
-	execNodes     []Component
-	tabletNodes   []Component
 	initUser      *InitJob
 	initOpArchive *InitJob
 	secret        *resources.StringSecret
@@ -33,8 +30,7 @@ type scheduler struct {
 func NewScheduler(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	master Component,
-	execNodes, tabletNodes []Component) Component {
+	dependencies DependencyChecker) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
 		ObjectMeta:     &resource.ObjectMeta,
@@ -59,14 +55,12 @@ func NewScheduler(
 
 	return &scheduler{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
-		server:      server,
-		master:      master,
-		execNodes:   execNodes,
-		tabletNodes: tabletNodes,
+		server: server,
 		initUser: NewInitJob(
 			&l,
 			ytsaurus.GetJobs(),
@@ -98,6 +92,14 @@ func (s *scheduler) IsUpdatable() bool {
 	return true
 }
 
+func (s *scheduler) IsManaged() bool {
+	return s.server.isManaged()
+}
+
+func (s *scheduler) GetPodsStatus() PodsStatus {
+	return s.server.getPodsStatus()
+}
+
 func (s *scheduler) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		s.server,
@@ -107,13 +109,8 @@ func (s *scheduler) Fetch(ctx context.Context) error {
 	)
 }
 
-func (s *scheduler) Status(ctx context.Context) ComponentStatus {
-	status, err := s.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (s *scheduler) Status(ctx context.Context) (ComponentStatus, error) {
+	return s.doSync(ctx, true)
 }
 
 func (s *scheduler) Sync(ctx context.Context) error {
@@ -124,43 +121,44 @@ func (s *scheduler) Sync(ctx context.Context) error {
 func (s *scheduler) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !s.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(s.ytsaurus.GetClusterState()) && s.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, s.server.getUpdateReason()), err
 	}
 
-	if s.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(s.ytsaurus.GetClusterState()) {
 		if IsUpdatingComponent(s.ytsaurus, s) {
 			if s.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval {
-				if !dry {
-					err = removePods(ctx, s.server, &s.componentBase)
+				if !s.server.isRollingUpdate() {
+					if !dry {
+						err = removePods(ctx, s.server, &s.componentBase)
+					}
+					return WaitingStatus(SyncStatusUpdating, "pods removal"), err
+				}
+				if status, err := updatePodsRolling(ctx, s.server, &s.componentBase, dry); status != nil {
+					return *status, err
+				}
+			} else {
+				if status, err := s.updateOpArchive(ctx, dry); status != nil {
+					return *status, err
 				}
-				return WaitingStatus(SyncStatusUpdating, "pods removal"), err
-			}
-
-			if status, err := s.updateOpArchive(ctx, dry); status != nil {
-				return *status, err
-			}
 
-			if s.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForPodsCreation &&
-				s.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForOpArchiveUpdate {
-				return NewComponentStatus(SyncStatusReady, "Nothing to do now"), err
+				if s.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForPodsCreation &&
+					s.ytsaurus.GetUpdateState() != ytv1.UpdateStateWaitingForOpArchiveUpdate {
+					return NewComponentStatus(SyncStatusReady, "Nothing to do now"), err
+				}
 			}
 		} else {
 			return NewComponentStatus(SyncStatusReady, "Not updating component"), err
 		}
 	}
 
-	if !IsRunningStatus(s.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, s.master.GetName()), err
-	}
-
-	if s.execNodes == nil || len(s.execNodes) > 0 {
-		for _, end := range s.execNodes {
-			if !IsRunningStatus(end.Status(ctx).SyncStatus) {
-				// It makes no sense to start scheduler without exec nodes.
-				return WaitingStatus(SyncStatusBlocked, end.GetName()), err
-			}
-		}
+	// It makes no sense to start scheduler without exec nodes.
+	if status := s.waitForDependencies(ctx, consts.YTComponentLabelMaster, consts.YTComponentLabelExecNode); status != nil {
+		return *status, err
 	}
 
 	if s.secret.NeedSync(consts.TokenSecretKey, "") {
@@ -203,11 +201,9 @@ func (s *scheduler) initOpAchieve(ctx context.Context, dry bool) (ComponentStatu
 		return status, err
 	}
 
-	for _, tnd := range s.tabletNodes {
-		if !IsRunningStatus(tnd.Status(ctx).SyncStatus) {
-			// Wait for tablet nodes to proceed with operations archive init.
-			return WaitingStatus(SyncStatusBlocked, tnd.GetName()), err
-		}
+	// Wait for tablet nodes to proceed with operations archive init.
+	if status := s.waitForDependencies(ctx, consts.YTComponentLabelTabletNode); status != nil {
+		return *status, err
 	}
 
 	if !dry {
@@ -244,8 +240,32 @@ func (s *scheduler) updateOpArchive(ctx context.Context, dry bool) (*ComponentSt
 	}
 }
 
+// GetUpdateSteps returns the steps of the operations archive update, they are skipped if the scheduler isn't updating.
+func (s *scheduler) GetUpdateSteps() []UpdateStep {
+	return []UpdateStep{
+		{
+			State:     ytv1.UpdateStateWaitingForOpArchiveUpdatingPrepare,
+			Message:   "Waiting for operations archive prepare for updating",
+			ShouldRun: func() bool { return IsUpdatingComponent(s.ytsaurus, s) },
+			IsDone: func() bool {
+				return s.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionOpArchivePreparedForUpdating) ||
+					s.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNotNecessaryToUpdateOpArchive)
+			},
+		},
+		{
+			State:   ytv1.UpdateStateWaitingForOpArchiveUpdate,
+			Message: "Waiting for operations archive updating to finish",
+			ShouldRun: func() bool {
+				return IsUpdatingComponent(s.ytsaurus, s) &&
+					!s.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionNotNecessaryToUpdateOpArchive)
+			},
+			IsDone: func() bool { return s.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionOpArchiveUpdated) },
+		},
+	}
+}
+
 func (s *scheduler) needOpArchiveInit() bool {
-	return s.tabletNodes != nil && len(s.tabletNodes) > 0
+	return len(s.ytsaurus.GetResource().Spec.TabletNodes) > 0
 }
 
 func (s *scheduler) setConditionNotNecessaryToUpdateOpArchive(ctx context.Context) {
@@ -49,6 +49,7 @@ func NewSecondaryMaster(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus,
 		cfgen.GetMasterCellStatefulSetName(spec.CellTag),
 		cfgen.GetSecondaryMastersServiceName(spec.CellTag),
 		func() ([]byte, error) { return cfgen.GetMasterCellConfig(spec) },
+		withQuorumDisruptionBudget(),
 	)
 	initJob := NewInitJob(
 		&l,
@@ -78,6 +79,14 @@ func (m *secondaryMaster) IsUpdatable() bool {
 	return true
 }
 
+func (m *secondaryMaster) IsManaged() bool {
+	return m.server.isManaged()
+}
+
+func (m *secondaryMaster) GetPodsStatus() PodsStatus {
+	return m.server.getPodsStatus()
+}
+
 func (m *secondaryMaster) Fetch(ctx context.Context) error {
 	if m.ytsaurus.GetResource().Spec.AdminCredentials != nil {
 		if err := m.ytsaurus.APIProxy().FetchObject(ctx,
@@ -112,11 +121,17 @@ func (m *secondaryMaster) createInitScript() string {
 func (m *secondaryMaster) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !m.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(m.ytsaurus.GetClusterState()) && m.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedFullUpdate), err
+		// Local update removes all pods at once, so even the restart of masters is done by the full update
+		// which keeps the masters read-only meanwhile.
+		return NewComponentStatus(SyncStatusNeedFullUpdate, m.server.getUpdateReason()), err
 	}
 
-	if m.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(m.ytsaurus.GetClusterState()) {
 		if m.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForMasterExitReadOnly {
 			st, err := m.exitReadOnly(ctx, dry)
 			return *st, err
@@ -144,13 +159,8 @@ func (m *secondaryMaster) doSync(ctx context.Context, dry bool) (ComponentStatus
 	return m.initJob.Sync(ctx, dry)
 }
 
-func (m *secondaryMaster) Status(ctx context.Context) ComponentStatus {
-	status, err := m.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (m *secondaryMaster) Status(ctx context.Context) (ComponentStatus, error) {
+	return m.doSync(ctx, true)
 }
 
 func (m *secondaryMaster) Sync(ctx context.Context) error {
@@ -2,12 +2,16 @@ package components
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"path"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ptr "k8s.io/utils/pointer"
+	"k8s.io/utils/strings/slices"
 
 	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
@@ -23,7 +27,14 @@ type server interface {
 	resources.Syncable
 	podsManager
 	needUpdate() bool
+	isRestartOnlyUpdate() bool
+	getUpdateReason() string
 	needSync() bool
+	isManaged() bool
+	isRollingUpdate() bool
+	arePodsUpdated() bool
+	getRollingUpdateProgress() (updated, total int32)
+	getReplicas() int32
 	buildStatefulSet() *appsv1.StatefulSet
 	rebuildStatefulSet() *appsv1.StatefulSet
 }
@@ -37,22 +48,37 @@ type serverImpl struct {
 
 	instanceSpec *ytv1.InstanceSpec
 
-	statefulSet       *resources.StatefulSet
-	headlessService   *resources.HeadlessService
-	monitoringService *resources.MonitoringService
-	caBundle          *resources.CABundle
-	tlsSecret         *resources.TLSSecret
-	configHelper      *ConfigHelper
+	statefulSet         *resources.StatefulSet
+	headlessService     *resources.HeadlessService
+	monitoringService   *resources.MonitoringService
+	podDisruptionBudget *resources.PodDisruptionBudget
+	caBundle            *resources.CABundle
+	tlsSecret           *resources.TLSSecret
+	configHelper        *ConfigHelper
+
+	// keepQuorum makes the default pod disruption budget keep the majority of pods available.
+	keepQuorum bool
 
 	builtStatefulSet *appsv1.StatefulSet
 }
 
+// serverOption customizes the server of a particular component.
+type serverOption func(s *serverImpl)
+
+// withQuorumDisruptionBudget is used for servers forming a hydra quorum, e.g. masters.
+func withQuorumDisruptionBudget() serverOption {
+	return func(s *serverImpl) {
+		s.keepQuorum = true
+	}
+}
+
 func newServer(
 	l *labeller.Labeller,
 	ytsaurus *apiproxy.Ytsaurus,
 	instanceSpec *ytv1.InstanceSpec,
 	binaryPath, configFileName, statefulSetName, serviceName string,
 	generator ytconfig.YsonGeneratorFunc,
+	options ...serverOption,
 ) server {
 	image := ytsaurus.GetResource().Spec.CoreImage
 	if instanceSpec.Image != nil {
@@ -77,7 +103,7 @@ func newServer(
 		)
 	}
 
-	return &serverImpl{
+	s := &serverImpl{
 		labeller:     l,
 		image:        image,
 		ytsaurus:     ytsaurus,
@@ -97,6 +123,10 @@ func newServer(
 			l,
 			ytsaurus.APIProxy(),
 		),
+		podDisruptionBudget: resources.NewPodDisruptionBudget(
+			l,
+			ytsaurus.APIProxy(),
+		),
 		caBundle:  caBundle,
 		tlsSecret: tlsSecret,
 		configHelper: NewConfigHelper(
@@ -111,6 +141,10 @@ func newServer(
 				},
 			}),
 	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
 }
 
 func (s *serverImpl) Fetch(ctx context.Context) error {
@@ -119,6 +153,7 @@ func (s *serverImpl) Fetch(ctx context.Context) error {
 		s.configHelper,
 		s.headlessService,
 		s.monitoringService,
+		s.podDisruptionBudget,
 	)
 }
 
@@ -134,15 +169,55 @@ func (s *serverImpl) needSync() bool {
 		needReload = false
 	}
 	return s.configHelper.NeedInit() ||
-		(s.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating && needReload) ||
+		(ytv1.IsUpdatingClusterState(s.ytsaurus.GetClusterState()) && needReload) ||
 		!s.exists() ||
-		s.statefulSet.NeedSync(s.instanceSpec.InstanceCount)
+		s.statefulSet.NeedSync(s.instanceSpec.InstanceCount) ||
+		s.podDisruptionBudget.NeedSync(s.getDisruptionBudget()) ||
+		s.needRollingUpdateStep()
+}
+
+// arePodsRecreated checks that the operator removes or creates pods of the server in the current update.
+func (s *serverImpl) arePodsRecreated() bool {
+	if !ytv1.IsUpdatingClusterState(s.ytsaurus.GetClusterState()) {
+		return false
+	}
+	updateState := s.ytsaurus.GetUpdateState()
+	if updateState != ytv1.UpdateStateWaitingForPodsRemoval && updateState != ytv1.UpdateStateWaitingForPodsCreation {
+		return false
+	}
+	componentNames := s.ytsaurus.GetLocalUpdatingComponents()
+	return componentNames == nil || slices.Contains(componentNames, s.labeller.ComponentName)
+}
+
+// getDisruptionBudget returns the limits of the pod disruption budget of the server.
+// The operator removes and creates pods of the updating components by itself, so evictions are not limited then.
+func (s *serverImpl) getDisruptionBudget() (minAvailable, maxUnavailable *intstr.IntOrString) {
+	if s.arePodsRecreated() {
+		allPods := intstr.FromString("100%")
+		return nil, &allPods
+	}
+
+	if spec := s.instanceSpec.PodDisruptionBudget; spec != nil && (spec.MinAvailable != nil || spec.MaxUnavailable != nil) {
+		return spec.MinAvailable, spec.MaxUnavailable
+	}
+
+	maxUnavailableCount := 1
+	if s.keepQuorum {
+		instanceCount := int(s.instanceSpec.InstanceCount)
+		maxUnavailableCount = instanceCount - (instanceCount/2 + 1)
+		if maxUnavailableCount < 0 {
+			maxUnavailableCount = 0
+		}
+	}
+	defaultMaxUnavailable := intstr.FromInt(maxUnavailableCount)
+	return nil, &defaultMaxUnavailable
 }
 
 func (s *serverImpl) Sync(ctx context.Context) error {
 	_ = s.configHelper.Build()
 	_ = s.headlessService.Build()
 	_ = s.monitoringService.Build()
+	_ = s.podDisruptionBudget.Build(s.getDisruptionBudget())
 	_ = s.buildStatefulSet()
 
 	return resources.Sync(ctx,
@@ -150,6 +225,7 @@ func (s *serverImpl) Sync(ctx context.Context) error {
 		s.configHelper,
 		s.headlessService,
 		s.monitoringService,
+		s.podDisruptionBudget,
 	)
 }
 
@@ -165,12 +241,141 @@ func (s *serverImpl) podsImageCorrespondsToSpec() bool {
 	return s.statefulSet.OldObject().(*appsv1.StatefulSet).Spec.Template.Spec.Containers[0].Image == s.image
 }
 
+func (s *serverImpl) podsConfigCorrespondsToSpec() bool {
+	configHash, err := s.configHelper.GetConfigHash()
+	if err != nil {
+		return false
+	}
+	return s.statefulSet.OldObject().(*appsv1.StatefulSet).Spec.Template.Annotations[consts.ConfigHashAnnotationName] == configHash
+}
+
+func (s *serverImpl) isManaged() bool {
+	return s.instanceSpec.Managed == nil || *s.instanceSpec.Managed
+}
+
+func (s *serverImpl) getRestartedAt() string {
+	if s.instanceSpec.RestartedAt == nil {
+		return ""
+	}
+	return s.instanceSpec.RestartedAt.UTC().Format(time.RFC3339)
+}
+
+// podsRestartCorrespondsToSpec checks that pods were created after the restart requested in the spec.
+// Removal of the request from the spec doesn't require another restart.
+func (s *serverImpl) podsRestartCorrespondsToSpec() bool {
+	restartedAt := s.getRestartedAt()
+	return restartedAt == "" ||
+		s.statefulSet.OldObject().(*appsv1.StatefulSet).Spec.Template.Annotations[consts.RestartedAtAnnotationName] == restartedAt
+}
+
+func (s *serverImpl) podsCorrespondToSpec() bool {
+	return s.podsImageCorrespondsToSpec() &&
+		s.podsConfigCorrespondsToSpec() &&
+		s.podsRestartCorrespondsToSpec()
+}
+
+func (s *serverImpl) hasRollingUpdateStrategy() bool {
+	return s.instanceSpec.UpdateStrategy == ytv1.ComponentUpdateStrategyRollingUpdate
+}
+
+// isRollingUpdate checks that pods of the server are replaced one by one during the current local update.
+// Hibernation bypasses the rolling update, the pods are always scaled down to zero then.
+func (s *serverImpl) isRollingUpdate() bool {
+	return s.hasRollingUpdateStrategy() &&
+		s.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating &&
+		slices.Contains(s.ytsaurus.GetLocalUpdatingComponents(), s.labeller.ComponentName)
+}
+
+// getPodsConfigHash returns the config hash the pod template is annotated with.
+// Config changes don't affect pod template, so config hash is required for the rolling update to restart pods.
+// The hash is changed only when the pods are created or replaced by the update, otherwise the existing one is kept,
+// so the sync doesn't change the template and restart the pods.
+func (s *serverImpl) getPodsConfigHash() string {
+	if s.hasRollingUpdateStrategy() && (!resources.Exists(s.statefulSet) || s.isRollingUpdate() || s.arePodsRecreated()) {
+		configHash, _ := s.configHelper.GetConfigHash()
+		return configHash
+	}
+	if !resources.Exists(s.statefulSet) {
+		return ""
+	}
+	return s.statefulSet.OldObject().(*appsv1.StatefulSet).Spec.Template.Annotations[consts.ConfigHashAnnotationName]
+}
+
+// getRollingUpdatePartition returns the partition to be applied on the next step of the rolling update.
+// Pods are updated starting from the highest ordinal, the partition is moved to the next pod
+// only when all already updated pods are ready. The rollback of the update resets the partition,
+// so all the replaced pods are brought back to the restored spec.
+func (s *serverImpl) getRollingUpdatePartition() int32 {
+	if s.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionUpdateRollback) {
+		return 0
+	}
+
+	if !s.podsCorrespondToSpec() {
+		if s.instanceSpec.InstanceCount > 0 {
+			return s.instanceSpec.InstanceCount - 1
+		}
+		return 0
+	}
+
+	partition := s.statefulSet.GetRollingUpdatePartition()
+	if partition > 0 && s.statefulSet.AreUpdatedPodsReady(s.instanceSpec.InstanceCount, partition) {
+		return partition - 1
+	}
+	return partition
+}
+
+func (s *serverImpl) needRollingUpdateStep() bool {
+	if !s.isRollingUpdate() || !s.exists() {
+		return false
+	}
+	return !s.podsCorrespondToSpec() ||
+		s.getRollingUpdatePartition() != s.statefulSet.GetRollingUpdatePartition()
+}
+
+func (s *serverImpl) arePodsUpdated() bool {
+	if !s.exists() {
+		return false
+	}
+	return s.podsCorrespondToSpec() &&
+		s.statefulSet.GetRollingUpdatePartition() == 0 &&
+		s.statefulSet.AreUpdatedPodsReady(s.instanceSpec.InstanceCount, 0)
+}
+
+func (s *serverImpl) getRollingUpdateProgress() (updated, total int32) {
+	total = s.instanceSpec.InstanceCount
+	if !s.exists() || !s.podsCorrespondToSpec() {
+		return 0, total
+	}
+	return s.statefulSet.GetUpdatedReplicas(), total
+}
+
+// getReplicas returns the number of replicas of the existing statefulset.
+func (s *serverImpl) getReplicas() int32 {
+	if !resources.Exists(s.statefulSet) {
+		return 0
+	}
+	return s.statefulSet.GetReplicas()
+}
+
+func (s *serverImpl) getPodsStatus() PodsStatus {
+	status := PodsStatus{}
+	if resources.Exists(s.statefulSet) {
+		statefulSet := s.statefulSet.OldObject().(*appsv1.StatefulSet)
+		status = getPodTemplateStatus(&statefulSet.Spec.Template)
+		status.ReadyReplicas = s.statefulSet.GetReadyReplicas()
+		status.UpdatedReplicas = s.statefulSet.GetUpdatedReplicas()
+	}
+	status.DesiredImage = s.image
+	status.DesiredReplicas = s.instanceSpec.InstanceCount
+	return status
+}
+
 func (s *serverImpl) needUpdate() bool {
 	if !s.exists() {
 		return false
 	}
 
-	if !s.podsImageCorrespondsToSpec() {
+	if !s.podsImageCorrespondsToSpec() || !s.podsRestartCorrespondsToSpec() {
 		return true
 	}
 
@@ -181,10 +386,65 @@ func (s *serverImpl) needUpdate() bool {
 	return needReload
 }
 
+// isRestartOnlyUpdate checks that the update is caused only by the restart requested in the spec,
+// such update is done locally even for components which require a full update.
+func (s *serverImpl) isRestartOnlyUpdate() bool {
+	if !s.exists() || !s.podsImageCorrespondsToSpec() || s.podsRestartCorrespondsToSpec() {
+		return false
+	}
+	needReload, err := s.configHelper.NeedReload()
+	return err == nil && !needReload
+}
+
+func (s *serverImpl) getUpdateReason() string {
+	if s.isRestartOnlyUpdate() {
+		return fmt.Sprintf("Restart requested at %s", s.getRestartedAt())
+	}
+	return getUpdateReason(s.podsImageCorrespondsToSpec(), s.image, s.configHelper)
+}
+
 func (s *serverImpl) arePodsReady(ctx context.Context) bool {
 	return s.statefulSet.ArePodsReady(ctx, s.instanceSpec.MinReadyInstanceCount)
 }
 
+// getProbeHandler requests the orchid of the server on the monitoring port,
+// it responds as soon as the server is bootstrapped.
+func (s *serverImpl) getProbeHandler() corev1.ProbeHandler {
+	return corev1.ProbeHandler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path: consts.OrchidServicePath,
+			Port: intstr.FromInt(int(s.labeller.MonitoringPort)),
+		},
+	}
+}
+
+func (s *serverImpl) getReadinessProbe() *corev1.Probe {
+	if s.instanceSpec.ReadinessProbe != nil {
+		return s.instanceSpec.ReadinessProbe
+	}
+	return &corev1.Probe{
+		ProbeHandler:     s.getProbeHandler(),
+		TimeoutSeconds:   5,
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+	}
+}
+
+// getLivenessProbe returns the probe which restarts hung servers,
+// it is more tolerant than the readiness probe since servers may respond slowly under load.
+func (s *serverImpl) getLivenessProbe() *corev1.Probe {
+	if s.instanceSpec.LivenessProbe != nil {
+		return s.instanceSpec.LivenessProbe
+	}
+	return &corev1.Probe{
+		ProbeHandler:        s.getProbeHandler(),
+		InitialDelaySeconds: 60,
+		TimeoutSeconds:      10,
+		PeriodSeconds:       30,
+		FailureThreshold:    5,
+	}
+}
+
 func (s *serverImpl) buildStatefulSet() *appsv1.StatefulSet {
 	if s.builtStatefulSet != nil {
 		return s.builtStatefulSet
@@ -218,11 +478,13 @@ func (s *serverImpl) rebuildStatefulSet() *appsv1.StatefulSet {
 		SetHostnameAsFQDN: &setHostnameAsFQDN,
 		Containers: []corev1.Container{
 			{
-				Image:        s.image,
-				Name:         consts.YTServerContainerName,
-				Command:      []string{s.binaryPath, "--config", path.Join(consts.ConfigMountPoint, fileNames[0])},
-				VolumeMounts: volumeMounts,
-				Resources:    s.instanceSpec.Resources,
+				Image:          s.image,
+				Name:           consts.YTServerContainerName,
+				Command:        []string{s.binaryPath, "--config", path.Join(consts.ConfigMountPoint, fileNames[0])},
+				VolumeMounts:   volumeMounts,
+				Resources:      s.instanceSpec.Resources,
+				ReadinessProbe: s.getReadinessProbe(),
+				LivenessProbe:  s.getLivenessProbe(),
 			},
 		},
 		InitContainers: []corev1.Container{
@@ -265,6 +527,29 @@ func (s *serverImpl) rebuildStatefulSet() *appsv1.StatefulSet {
 		s.tlsSecret.AddVolumeMount(&statefulSet.Spec.Template.Spec.Containers[0])
 	}
 
+	if configHash := s.getPodsConfigHash(); configHash != "" {
+		statefulSet.Spec.Template.Annotations = labeller.Join(
+			statefulSet.Spec.Template.Annotations,
+			map[string]string{consts.ConfigHashAnnotationName: configHash},
+		)
+	}
+
+	if restartedAt := s.getRestartedAt(); restartedAt != "" {
+		statefulSet.Spec.Template.Annotations = labeller.Join(
+			statefulSet.Spec.Template.Annotations,
+			map[string]string{consts.RestartedAtAnnotationName: restartedAt},
+		)
+	}
+
+	if s.isRollingUpdate() && s.exists() {
+		statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: ptr.Int32(s.getRollingUpdatePartition()),
+			},
+		}
+	}
+
 	s.builtStatefulSet = statefulSet
 	return statefulSet
 }
@@ -0,0 +1,360 @@
+package components
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	ptr "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
+)
+
+var _ = Describe("Server restart test", func() {
+	var ytsaurusSpec *v1.Ytsaurus
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ytsaurusSpec = &v1.Ytsaurus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ytsaurus",
+				Namespace: "default",
+			},
+			Spec: v1.YtsaurusSpec{
+				CoreImage: "ytsaurus/ytsaurus:latest",
+				Discovery: v1.DiscoverySpec{
+					InstanceSpec: v1.InstanceSpec{
+						InstanceCount: 1,
+					},
+				},
+			},
+			Status: v1.YtsaurusStatus{
+				State: v1.ClusterStateRunning,
+			},
+		}
+
+		scheme = runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(policyv1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("Requested restart is a local update", func() {
+		ctx := context.Background()
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+
+		d := NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.Sync(ctx)).Should(Succeed())
+
+		d = NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.needUpdate()).Should(BeFalse())
+
+		restartedAt := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		ytsaurusSpec.Spec.Discovery.RestartedAt = &restartedAt
+
+		d = NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.needUpdate()).Should(BeTrue())
+		Expect(d.server.isRestartOnlyUpdate()).Should(BeTrue())
+		Expect(d.server.getUpdateReason()).Should(Equal("Restart requested at 2024-01-02T03:04:05Z"))
+
+		Expect(d.server.rebuildStatefulSet().Spec.Template.Annotations).Should(
+			HaveKeyWithValue("ytsaurus.tech/restarted-at", "2024-01-02T03:04:05Z"))
+		Expect(d.server.Sync(ctx)).Should(Succeed())
+
+		d = NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.needUpdate()).Should(BeFalse())
+
+		// Removal of the request doesn't restart pods again.
+		ytsaurusSpec.Spec.Discovery.RestartedAt = nil
+		d = NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.needUpdate()).Should(BeFalse())
+	})
+
+	It("Requested restart of masters is a full update", func() {
+		ctx := context.Background()
+		ytsaurusSpec.Spec.PrimaryMasters.InstanceCount = 3
+		ytsaurusSpec.Spec.PrimaryMasters.Locations = []v1.LocationSpec{
+			{LocationType: v1.LocationTypeMasterChangelogs, Path: "/yt/master-data/master-changelogs"},
+			{LocationType: v1.LocationTypeMasterSnapshots, Path: "/yt/master-data/master-snapshots"},
+		}
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+
+		m := NewMaster(cfgen, ytsaurus).(*master)
+		Expect(m.Fetch(ctx)).Should(Succeed())
+		Expect(m.server.Sync(ctx)).Should(Succeed())
+
+		restartedAt := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		ytsaurusSpec.Spec.PrimaryMasters.RestartedAt = &restartedAt
+
+		m = NewMaster(cfgen, ytsaurus).(*master)
+		Expect(m.Fetch(ctx)).Should(Succeed())
+		Expect(m.server.isRestartOnlyUpdate()).Should(BeTrue())
+		Expect(getStatus(m)).Should(Equal(NewComponentStatus(SyncStatusNeedFullUpdate, "Restart requested at 2024-01-02T03:04:05Z")))
+	})
+
+	It("Server container has probes on the monitoring port", func() {
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+		livenessProbe := &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(consts.DiscoveryRPCPort)},
+			},
+		}
+		ytsaurusSpec.Spec.Discovery.LivenessProbe = livenessProbe
+
+		d := NewDiscovery(cfgen, ytsaurus).(*discovery)
+		container := d.server.rebuildStatefulSet().Spec.Template.Spec.Containers[0]
+		Expect(container.ReadinessProbe.HTTPGet).Should(Equal(&corev1.HTTPGetAction{
+			Path: "/orchid/service",
+			Port: intstr.FromInt(consts.DiscoveryMonitoringPort),
+		}))
+		Expect(container.LivenessProbe).Should(Equal(livenessProbe))
+	})
+
+	It("Masters disruption budget keeps the quorum", func() {
+		ctx := context.Background()
+		ytsaurusSpec.Spec.PrimaryMasters.InstanceCount = 5
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+
+		m := NewMaster(cfgen, ytsaurus).(*master)
+		Expect(m.server.Fetch(ctx)).Should(Succeed())
+		Expect(m.server.Sync(ctx)).Should(Succeed())
+
+		var pdb policyv1.PodDisruptionBudget
+		Expect(client.Get(ctx, types.NamespacedName{Name: "yt-master-pdb", Namespace: "default"}, &pdb)).Should(Succeed())
+		Expect(*pdb.Spec.MaxUnavailable).Should(Equal(intstr.FromInt(2)))
+
+		// Budget keeps the quorum of masters which are not updated.
+		ytsaurusSpec.Status.State = v1.ClusterStateUpdating
+		ytsaurusSpec.Status.UpdateStatus.State = v1.UpdateStateWaitingForPodsRemoval
+		ytsaurusSpec.Status.UpdateStatus.Components = []string{"Discovery"}
+		m = NewMaster(cfgen, ytsaurus).(*master)
+		Expect(m.server.Fetch(ctx)).Should(Succeed())
+		Expect(m.server.needSync()).Should(BeFalse())
+
+		// Budget keeps the quorum of updating masters until their pods are removed.
+		ytsaurusSpec.Status.UpdateStatus.State = v1.UpdateStateWaitingForSnapshots
+		ytsaurusSpec.Status.UpdateStatus.Components = nil
+		m = NewMaster(cfgen, ytsaurus).(*master)
+		Expect(m.server.Fetch(ctx)).Should(Succeed())
+		Expect(m.server.needSync()).Should(BeFalse())
+
+		// Pods are removed by the operator during updates, the budget doesn't block it.
+		ytsaurusSpec.Status.UpdateStatus.State = v1.UpdateStateWaitingForPodsRemoval
+		m = NewMaster(cfgen, ytsaurus).(*master)
+		Expect(m.server.Fetch(ctx)).Should(Succeed())
+		Expect(m.server.needSync()).Should(BeTrue())
+		Expect(m.server.removePods(ctx)).Should(Succeed())
+		Expect(client.Get(ctx, types.NamespacedName{Name: "yt-master-pdb", Namespace: "default"}, &pdb)).Should(Succeed())
+		Expect(*pdb.Spec.MaxUnavailable).Should(Equal(intstr.FromString("100%")))
+	})
+
+	It("Disruption budget is configurable", func() {
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+
+		d := NewDiscovery(cfgen, ytsaurus).(*discovery)
+		minAvailable, maxUnavailable := d.server.(*serverImpl).getDisruptionBudget()
+		Expect(minAvailable).Should(BeNil())
+		Expect(*maxUnavailable).Should(Equal(intstr.FromInt(1)))
+
+		halfPods := intstr.FromString("50%")
+		ytsaurusSpec.Spec.Discovery.PodDisruptionBudget = &v1.PodDisruptionBudgetSpec{MinAvailable: &halfPods}
+		minAvailable, maxUnavailable = d.server.(*serverImpl).getDisruptionBudget()
+		Expect(minAvailable).Should(Equal(&halfPods))
+		Expect(maxUnavailable).Should(BeNil())
+	})
+
+	It("Unmanaged component is not synced", func() {
+		ctx := context.Background()
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+		ytsaurusSpec.Spec.Discovery.Managed = ptr.Bool(false)
+
+		d := NewDiscovery(cfgen, ytsaurus)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(getStatus(d)).Should(Equal(SimpleStatus(SyncStatusUnmanaged)))
+		Expect(IsRunningStatus(getStatus(d).SyncStatus)).Should(BeTrue())
+		Expect(IsUpdatingComponent(ytsaurus, d)).Should(BeFalse())
+
+		Expect(d.Sync(ctx)).Should(Succeed())
+		var statefulSets appsv1.StatefulSetList
+		Expect(client.List(ctx, &statefulSets)).Should(Succeed())
+		Expect(statefulSets.Items).Should(BeEmpty())
+	})
+
+	It("Hibernation removes pods of the rolling update component", func() {
+		ctx := context.Background()
+		ytsaurusSpec.Spec.Discovery.UpdateStrategy = v1.ComponentUpdateStrategyRollingUpdate
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+
+		d := NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.Sync(ctx)).Should(Succeed())
+
+		ytsaurusSpec.Status.State = v1.ClusterStateHibernating
+		ytsaurusSpec.Status.UpdateStatus.State = v1.UpdateStateWaitingForPodsRemoval
+		d = NewDiscovery(cfgen, ytsaurus).(*discovery)
+		Expect(d.Fetch(ctx)).Should(Succeed())
+		Expect(d.server.isRollingUpdate()).Should(BeFalse())
+		Expect(getStatus(d)).Should(Equal(WaitingStatus(SyncStatusUpdating, "pods removal")))
+		Expect(d.Sync(ctx)).Should(Succeed())
+
+		var sts appsv1.StatefulSet
+		Expect(client.Get(ctx, types.NamespacedName{Name: cfgen.GetDiscoveryStatefulSetName(), Namespace: "default"}, &sts)).
+			Should(Succeed())
+		Expect(*sts.Spec.Replicas).Should(Equal(int32(0)))
+	})
+
+	It("Rolling update moves the partition when updated pods are ready", func() {
+		ctx := context.Background()
+		ytsaurusSpec.Spec.Discovery.InstanceCount = 3
+		ytsaurusSpec.Spec.Discovery.UpdateStrategy = v1.ComponentUpdateStrategyRollingUpdate
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+
+		fetchServer := func() *serverImpl {
+			d := NewDiscovery(cfgen, ytsaurus).(*discovery)
+			Expect(d.Fetch(ctx)).Should(Succeed())
+			return d.server.(*serverImpl)
+		}
+		// Pods of the statefulset are not run by the fake client, so their state is set in the fetched object.
+		setPodsStatus := func(s *serverImpl, updated, ready int32) {
+			sts := s.statefulSet.OldObject().(*appsv1.StatefulSet)
+			sts.Status.ObservedGeneration = sts.Generation
+			sts.Status.UpdatedReplicas = updated
+			sts.Status.ReadyReplicas = ready
+		}
+
+		Expect(fetchServer().Sync(ctx)).Should(Succeed())
+		s := fetchServer()
+		Expect(s.isRollingUpdate()).Should(BeFalse())
+		Expect(s.needRollingUpdateStep()).Should(BeFalse())
+
+		ytsaurusSpec.Spec.CoreImage = "ytsaurus/ytsaurus:new"
+		ytsaurusSpec.Status.State = v1.ClusterStateUpdating
+		ytsaurusSpec.Status.UpdateStatus.Components = []string{"Discovery"}
+		s = fetchServer()
+		Expect(s.isRollingUpdate()).Should(BeTrue())
+		// The first step replaces the pod with the highest ordinal.
+		Expect(s.getRollingUpdatePartition()).Should(Equal(int32(2)))
+		Expect(s.needRollingUpdateStep()).Should(BeTrue())
+		Expect(s.arePodsUpdated()).Should(BeFalse())
+		Expect(s.Sync(ctx)).Should(Succeed())
+
+		s = fetchServer()
+		Expect(s.statefulSet.GetRollingUpdatePartition()).Should(Equal(int32(2)))
+		// The partition isn't moved until the new pod is ready.
+		setPodsStatus(s, 1, 2)
+		Expect(s.getRollingUpdatePartition()).Should(Equal(int32(2)))
+		Expect(s.needRollingUpdateStep()).Should(BeFalse())
+		Expect(s.arePodsUpdated()).Should(BeFalse())
+
+		setPodsStatus(s, 1, 3)
+		Expect(s.getRollingUpdatePartition()).Should(Equal(int32(1)))
+		Expect(s.needRollingUpdateStep()).Should(BeTrue())
+		updated, total := s.getRollingUpdateProgress()
+		Expect([]int32{updated, total}).Should(Equal([]int32{1, 3}))
+		Expect(s.Sync(ctx)).Should(Succeed())
+
+		s = fetchServer()
+		Expect(s.statefulSet.GetRollingUpdatePartition()).Should(Equal(int32(1)))
+		setPodsStatus(s, 2, 3)
+		Expect(s.getRollingUpdatePartition()).Should(Equal(int32(0)))
+		Expect(s.Sync(ctx)).Should(Succeed())
+
+		s = fetchServer()
+		Expect(s.statefulSet.GetRollingUpdatePartition()).Should(Equal(int32(0)))
+		setPodsStatus(s, 2, 3)
+		Expect(s.getRollingUpdatePartition()).Should(Equal(int32(0)))
+		Expect(s.needRollingUpdateStep()).Should(BeFalse())
+		Expect(s.arePodsUpdated()).Should(BeFalse())
+
+		setPodsStatus(s, 3, 3)
+		Expect(s.arePodsUpdated()).Should(BeTrue())
+
+		// The status of the previous generation of the statefulset is not trusted.
+		s.statefulSet.OldObject().(*appsv1.StatefulSet).Generation++
+		Expect(s.statefulSet.AreUpdatedPodsReady(3, 0)).Should(BeFalse())
+		Expect(s.arePodsUpdated()).Should(BeFalse())
+	})
+
+	It("Rolling update component keeps config hash outside of updates", func() {
+		ctx := context.Background()
+		ytsaurusSpec.Spec.Discovery.InstanceCount = 3
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ytsaurusSpec).Build()
+		ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(100), scheme)
+		cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+		fetchServer := func() *serverImpl {
+			d := NewDiscovery(cfgen, ytsaurus).(*discovery)
+			Expect(d.Fetch(ctx)).Should(Succeed())
+			return d.server.(*serverImpl)
+		}
+
+		// The strategy is switched for the running cluster, the sync doesn't restart pods.
+		Expect(fetchServer().Sync(ctx)).Should(Succeed())
+		ytsaurusSpec.Spec.Discovery.UpdateStrategy = v1.ComponentUpdateStrategyRollingUpdate
+		s := fetchServer()
+		Expect(s.rebuildStatefulSet().Spec.Template.Annotations).ShouldNot(HaveKey(consts.ConfigHashAnnotationName))
+		Expect(s.podsConfigCorrespondsToSpec()).Should(BeFalse())
+
+		// The rolling update stamps the hash of the new config.
+		ytsaurusSpec.Status.State = v1.ClusterStateUpdating
+		ytsaurusSpec.Status.UpdateStatus.Components = []string{"Discovery"}
+		s = fetchServer()
+		Expect(s.Sync(ctx)).Should(Succeed())
+		configHash, err := s.configHelper.GetConfigHash()
+		Expect(err).Should(Succeed())
+		s = fetchServer()
+		Expect(s.podsConfigCorrespondsToSpec()).Should(BeTrue())
+		Expect(s.statefulSet.GetRollingUpdatePartition()).Should(Equal(int32(2)))
+
+		// The rollback brings all the replaced pods back.
+		ytsaurusSpec.Status.UpdateStatus.Conditions = []metav1.Condition{
+			{Type: consts.ConditionUpdateRollback, Status: metav1.ConditionTrue, Reason: "UpdateStateTimeout"},
+		}
+		s = fetchServer()
+		Expect(s.getRollingUpdatePartition()).Should(Equal(int32(0)))
+		Expect(s.needRollingUpdateStep()).Should(BeTrue())
+
+		// The hash is kept by the sync of the running cluster.
+		ytsaurusSpec.Status.State = v1.ClusterStateRunning
+		ytsaurusSpec.Status.UpdateStatus.Components = nil
+		ytsaurusSpec.Status.UpdateStatus.Conditions = nil
+		Expect(fetchServer().rebuildStatefulSet().Spec.Template.Annotations).Should(
+			HaveKeyWithValue(consts.ConfigHashAnnotationName, configHash))
+	})
+})
@@ -166,13 +166,8 @@ func (s *Spyt) Fetch(ctx context.Context) error {
 	)
 }
 
-func (s *Spyt) Status(ctx context.Context) ComponentStatus {
-	status, err := s.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (s *Spyt) Status(ctx context.Context) (ComponentStatus, error) {
+	return s.doSync(ctx, true)
 }
 
 func (s *Spyt) Sync(ctx context.Context) error {
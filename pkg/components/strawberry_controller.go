@@ -23,10 +23,6 @@ type strawberryController struct {
 	initChytClusterJob *InitJob
 	secret             *resources.StringSecret
 
-	master    Component
-	scheduler Component
-	dataNodes []Component
-
 	name string
 }
 
@@ -43,9 +39,7 @@ const ChytInitClusterJobConfigFileName = "chyt-init-cluster.yson"
 func NewStrawberryController(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	master Component,
-	scheduler Component,
-	dataNodes []Component) Component {
+	dependencies DependencyChecker) Component {
 	resource := ytsaurus.GetResource()
 
 	image := resource.Spec.CoreImage
@@ -101,9 +95,10 @@ func NewStrawberryController(
 
 	return &strawberryController{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		microservice: svc,
 		initUserAndUrlJob: NewInitJob(
@@ -133,10 +128,7 @@ func NewStrawberryController(
 			&l,
 			ytsaurus.APIProxy(),
 		),
-		name:      name,
-		master:    master,
-		scheduler: scheduler,
-		dataNodes: dataNodes,
+		name: name,
 	}
 }
 
@@ -144,6 +136,10 @@ func (c *strawberryController) IsUpdatable() bool {
 	return true
 }
 
+func (c *strawberryController) GetPodsStatus() PodsStatus {
+	return c.microservice.getPodsStatus()
+}
+
 func (c *strawberryController) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		c.microservice,
@@ -236,10 +232,10 @@ func (c *strawberryController) doSync(ctx context.Context, dry bool) (ComponentS
 	var err error
 
 	if ytv1.IsReadyToUpdateClusterState(c.ytsaurus.GetClusterState()) && c.microservice.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, c.microservice.getUpdateReason()), err
 	}
 
-	if c.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(c.ytsaurus.GetClusterState()) {
 		if IsUpdatingComponent(c.ytsaurus, c) {
 			if c.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval {
 				if !dry {
@@ -256,18 +252,13 @@ func (c *strawberryController) doSync(ctx context.Context, dry bool) (ComponentS
 		}
 	}
 
-	if !IsRunningStatus(c.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, c.master.GetName()), err
-	}
-
-	if !IsRunningStatus(c.scheduler.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, c.scheduler.GetName()), err
-	}
-
-	for _, dataNode := range c.dataNodes {
-		if !IsRunningStatus(dataNode.Status(ctx).SyncStatus) {
-			return WaitingStatus(SyncStatusBlocked, dataNode.GetName()), err
-		}
+	if status := c.waitForDependencies(
+		ctx,
+		consts.YTComponentLabelMaster,
+		consts.YTComponentLabelScheduler,
+		consts.YTComponentLabelDataNode,
+	); status != nil {
+		return *status, err
 	}
 
 	if c.secret.NeedSync(consts.TokenSecretKey, "") {
@@ -307,13 +298,8 @@ func (c *strawberryController) doSync(ctx context.Context, dry bool) (ComponentS
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (c *strawberryController) Status(ctx context.Context) ComponentStatus {
-	status, err := c.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (c *strawberryController) Status(ctx context.Context) (ComponentStatus, error) {
+	return c.doSync(ctx, true)
 }
 
 func (c *strawberryController) Sync(ctx context.Context) error {
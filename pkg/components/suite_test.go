@@ -5,6 +5,7 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
 	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
 	"go.ytsaurus.tech/yt/go/yt"
 	appsv1 "k8s.io/api/apps/v1"
@@ -29,12 +30,18 @@ var _ = BeforeSuite(func() {
 })
 
 type FakeComponent struct {
-	name   string
-	status ComponentStatus
+	name      string
+	label     string
+	status    ComponentStatus
+	statusErr error
 }
 
 func NewFakeComponent(name string) *FakeComponent {
-	return &FakeComponent{name: name, status: SimpleStatus(SyncStatusReady)}
+	return NewFakeComponentWithLabel(name, name)
+}
+
+func NewFakeComponentWithLabel(name, label string) *FakeComponent {
+	return &FakeComponent{name: name, label: label, status: SimpleStatus(SyncStatusReady)}
 }
 
 func (fc *FakeComponent) IsUpdatable() bool {
@@ -49,8 +56,8 @@ func (fc *FakeComponent) Sync(ctx context.Context) error {
 	return nil
 }
 
-func (fc *FakeComponent) Status(ctx context.Context) ComponentStatus {
-	return fc.status
+func (fc *FakeComponent) Status(ctx context.Context) (ComponentStatus, error) {
+	return fc.status, fc.statusErr
 }
 
 func (fc *FakeComponent) IsUpdating() bool {
@@ -62,7 +69,7 @@ func (fc *FakeComponent) GetName() string {
 }
 
 func (fc *FakeComponent) GetLabel() string {
-	return fc.name
+	return fc.label
 }
 
 func (fc *FakeComponent) SetReadyCondition(status ComponentStatus) {}
@@ -83,6 +90,14 @@ func (fs *FakeServer) needUpdate() bool {
 	return false
 }
 
+func (fs *FakeServer) isRestartOnlyUpdate() bool {
+	return false
+}
+
+func (fs *FakeServer) getUpdateReason() string {
+	return ""
+}
+
 func (fs *FakeServer) podsImageCorrespondsToSpec() bool {
 	return true
 }
@@ -95,6 +110,30 @@ func (fs *FakeServer) arePodsRemoved(ctx context.Context) bool {
 	return true
 }
 
+func (fs *FakeServer) isManaged() bool {
+	return true
+}
+
+func (fs *FakeServer) isRollingUpdate() bool {
+	return false
+}
+
+func (fs *FakeServer) arePodsUpdated() bool {
+	return true
+}
+
+func (fs *FakeServer) getRollingUpdateProgress() (updated, total int32) {
+	return 0, 0
+}
+
+func (fs *FakeServer) getReplicas() int32 {
+	return 0
+}
+
+func (fs *FakeServer) getPodsStatus() PodsStatus {
+	return PodsStatus{}
+}
+
 func (fs *FakeServer) arePodsReady(ctx context.Context) bool {
 	return fs.podsReady
 }
@@ -126,7 +165,7 @@ type FakeYtsaurusClient struct {
 
 func NewFakeYtsaurusClient(client *mock_yt.MockClient) *FakeYtsaurusClient {
 	return &FakeYtsaurusClient{
-		FakeComponent: *NewFakeComponent("ytsaurus_client"),
+		FakeComponent: *NewFakeComponentWithLabel("ytsaurus_client", consts.YTComponentLabelClient),
 		client:        client,
 	}
 }
@@ -135,10 +174,52 @@ func (fyc *FakeYtsaurusClient) GetYtClient() yt.Client {
 	return fyc.client
 }
 
+func (fyc *FakeYtsaurusClient) CheckHealth(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 func (fyc *FakeYtsaurusClient) SetStatus(status ComponentStatus) {
 	fyc.status = status
 }
 
+func (fyc *FakeYtsaurusClient) SetStatusError(err error) {
+	fyc.statusErr = err
+}
+
+// FakeDependencyChecker declares the same dependencies for any component, as the component graph of the manager does.
+// The labels are matched exactly, the instance groups are not known to it.
+type FakeDependencyChecker struct {
+	dependencies []Component
+}
+
+func NewFakeDependencyChecker(dependencies ...Component) *FakeDependencyChecker {
+	return &FakeDependencyChecker{dependencies: dependencies}
+}
+
+func (fdc *FakeDependencyChecker) GetBlockingDependency(ctx context.Context, component string, labels ...string) string {
+	for _, dependency := range fdc.dependencies {
+		matched := len(labels) == 0
+		for _, label := range labels {
+			matched = matched || dependency.GetLabel() == label
+		}
+		if !matched {
+			continue
+		}
+		status, err := dependency.Status(ctx)
+		if err != nil || !IsRunningStatus(status.SyncStatus) ||
+			dependency.GetLabel() == consts.YTComponentLabelClient && status.SyncStatus != SyncStatusReady {
+			return dependency.GetName()
+		}
+	}
+	return ""
+}
+
+func getStatus(component Component) ComponentStatus {
+	status, err := component.Status(context.Background())
+	Expect(err).ShouldNot(HaveOccurred())
+	return status
+}
+
 func (fc *FakeYtsaurusClient) IsUpdatable() bool {
 	return false
 }
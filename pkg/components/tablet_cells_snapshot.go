@@ -0,0 +1,221 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yson"
+	"go.ytsaurus.tech/yt/go/yt"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const tabletCellsSnapshotKey = "tablet-cells-snapshot.yson"
+
+var (
+	// Bundle attributes which are restored after the full update.
+	tabletCellBundleSnapshotAttributes = []string{
+		"options",
+		"dynamic_options",
+		"node_tag_filter",
+		"tablet_balancer_config",
+	}
+	areaSnapshotAttributes = []string{
+		"name",
+		"cell_bundle",
+		"node_tag_filter",
+	}
+	// Tablet cell attributes which can be set on the cell creation and the peers,
+	// which are placed to the saved nodes after the creation.
+	tabletCellSnapshotAttributes = []string{
+		"tablet_cell_bundle",
+		"area",
+		"peer_count",
+		"peers",
+	}
+)
+
+// CypressObjectSnapshot is a Cypress node with the saved attributes.
+type CypressObjectSnapshot struct {
+	Name       string         `yson:",value"`
+	Attributes map[string]any `yson:",attrs"`
+}
+
+// TabletCellsSnapshot keeps the configuration of tablet cell bundles, areas and tablet cells,
+// which is saved before tablet cells removal during the full update.
+type TabletCellsSnapshot struct {
+	Bundles []CypressObjectSnapshot `yson:"bundles"`
+	Areas   []CypressObjectSnapshot `yson:"areas"`
+	Cells   []CypressObjectSnapshot `yson:"cells"`
+}
+
+func listCypressObjects(ctx context.Context, ytClient yt.Client, path string, attributes []string) ([]CypressObjectSnapshot, error) {
+	var objects []CypressObjectSnapshot
+	err := ytClient.ListNode(ctx, ypath.Path(path), &objects, &yt.ListNodeOptions{Attributes: attributes})
+	return objects, err
+}
+
+func GetTabletCellsSnapshot(ctx context.Context, ytClient yt.Client) (*TabletCellsSnapshot, error) {
+	var snapshot TabletCellsSnapshot
+	var err error
+
+	snapshot.Bundles, err = listCypressObjects(ctx, ytClient, "//sys/tablet_cell_bundles", tabletCellBundleSnapshotAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Areas are not supported by old versions of YTsaurus.
+	areasExist, err := ytClient.NodeExists(ctx, ypath.Path("//sys/areas"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if areasExist {
+		snapshot.Areas, err = listCypressObjects(ctx, ytClient, "//sys/areas", areaSnapshotAttributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot.Cells, err = listCypressObjects(ctx, ytClient, "//sys/tablet_cells", tabletCellSnapshotAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+func getTabletCellKey(bundle, area any) string {
+	return fmt.Sprintf("%v/%v", bundle, area)
+}
+
+// getTabletCellPeerAddresses returns the node addresses of the saved cell peers by their indexes,
+// the address is empty if the peer wasn't assigned to a node.
+func getTabletCellPeerAddresses(peers any) []string {
+	peerList, _ := peers.([]any)
+	addresses := make([]string, 0, len(peerList))
+	for _, peer := range peerList {
+		peerAttributes, _ := peer.(map[string]any)
+		address, _ := peerAttributes["address"].(string)
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// createTabletCell creates the tablet cell with the saved attributes and places its peers to the saved nodes.
+func createTabletCell(ctx context.Context, ytClient yt.Client, cell CypressObjectSnapshot) error {
+	logger := log.FromContext(ctx)
+
+	attributes := make(map[string]any, len(cell.Attributes))
+	for name, value := range cell.Attributes {
+		if name != "peers" {
+			attributes[name] = value
+		}
+	}
+	cellID, err := ytClient.CreateObject(ctx, "tablet_cell", &yt.CreateObjectOptions{
+		Attributes: attributes,
+	})
+	if err != nil {
+		logger.Error(err, "Creating tablet_cell failed")
+		return err
+	}
+
+	for index, address := range getTabletCellPeerAddresses(cell.Attributes["peers"]) {
+		if address == "" {
+			continue
+		}
+		path := ypath.Path("//sys/tablet_cells").Child(cellID.String()).Attr("peers").Child(strconv.Itoa(index)).Child("address")
+		if err := ytClient.SetNode(ctx, path, address, nil); err != nil {
+			logger.Error(err, "Restoring tablet cell peer failed", "cell", cellID, "peer", index, "address", address)
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreTabletCellsSnapshot restores attributes of bundles, creates missing areas
+// and creates tablet cells until their count in each bundle and area matches the snapshot.
+// Peers of the created cells are placed to the nodes which hosted the saved cells.
+func RestoreTabletCellsSnapshot(ctx context.Context, ytClient yt.Client, snapshot *TabletCellsSnapshot) error {
+	logger := log.FromContext(ctx)
+
+	for _, bundle := range snapshot.Bundles {
+		for name, value := range bundle.Attributes {
+			path := ypath.Path("//sys/tablet_cell_bundles").Child(bundle.Name).Attr(name)
+			if err := ytClient.SetNode(ctx, path, value, nil); err != nil {
+				logger.Error(err, "Restoring tablet cell bundle attribute failed", "bundle", bundle.Name, "attribute", name)
+				return err
+			}
+		}
+	}
+
+	for _, area := range snapshot.Areas {
+		exists, err := ytClient.NodeExists(ctx, ypath.Path("//sys/areas").Child(area.Name), nil)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := ytClient.CreateObject(ctx, yt.NodeType("area"), &yt.CreateObjectOptions{
+			Attributes: area.Attributes,
+		}); err != nil {
+			logger.Error(err, "Creating area failed", "area", area.Name)
+			return err
+		}
+	}
+
+	currentCells, err := listCypressObjects(ctx, ytClient, "//sys/tablet_cells", tabletCellSnapshotAttributes)
+	if err != nil {
+		return err
+	}
+	existingCellCounts := make(map[string]int)
+	for _, cell := range currentCells {
+		existingCellCounts[getTabletCellKey(cell.Attributes["tablet_cell_bundle"], cell.Attributes["area"])] += 1
+	}
+
+	for _, cell := range snapshot.Cells {
+		key := getTabletCellKey(cell.Attributes["tablet_cell_bundle"], cell.Attributes["area"])
+		if existingCellCounts[key] > 0 {
+			existingCellCounts[key] -= 1
+			continue
+		}
+		if err := createTabletCell(ctx, ytClient, cell); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (yc *ytsaurusClient) saveTabletCellsSnapshot(ctx context.Context) error {
+	snapshot, err := GetTabletCellsSnapshot(ctx, yc.ytClient)
+	if err != nil {
+		return err
+	}
+
+	data, err := yson.MarshalFormat(snapshot, yson.FormatPretty)
+	if err != nil {
+		return err
+	}
+
+	cm := yc.tabletCellsSnapshot.Build()
+	cm.Data[tabletCellsSnapshotKey] = string(data)
+	return yc.tabletCellsSnapshot.Sync(ctx)
+}
+
+// loadTabletCellsSnapshot returns nil if the snapshot wasn't saved,
+// e.g. when the update was started by the previous version of the operator.
+func (yc *ytsaurusClient) loadTabletCellsSnapshot() (*TabletCellsSnapshot, error) {
+	data, ok := yc.tabletCellsSnapshot.OldObject().(*corev1.ConfigMap).Data[tabletCellsSnapshotKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var snapshot TabletCellsSnapshot
+	if err := yson.Unmarshal([]byte(data), &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,81 @@
+package components
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.ytsaurus.tech/yt/go/guid"
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+
+	mock_yt "github.com/ytsaurus/yt-k8s-operator/pkg/mock"
+)
+
+var _ = Describe("Tablet cells snapshot test", func() {
+	It("Restore creates only missing tablet cells and places their peers", func() {
+		mockYtClient := mock_yt.NewMockClient(ctrl)
+
+		cellAttributes := map[string]any{
+			"tablet_cell_bundle": "sys",
+			"area":               "default",
+		}
+		savedCellAttributes := map[string]any{
+			"tablet_cell_bundle": "sys",
+			"area":               "default",
+			"peers": []any{
+				map[string]any{"address": "tnd-0.tablet-nodes:9022", "state": "leading"},
+				map[string]any{"state": "none"},
+			},
+		}
+		snapshot := &TabletCellsSnapshot{
+			Bundles: []CypressObjectSnapshot{
+				{Name: "sys", Attributes: map[string]any{"node_tag_filter": "sys_nodes"}},
+			},
+			Cells: []CypressObjectSnapshot{
+				{Name: "1-1-1-1", Attributes: savedCellAttributes},
+				{Name: "2-2-2-2", Attributes: savedCellAttributes},
+			},
+		}
+		cellID := yt.NodeID(guid.New())
+
+		gomock.InOrder(
+			mockYtClient.EXPECT().
+				SetNode(
+					gomock.Any(),
+					gomock.Eq(ypath.Path("//sys/tablet_cell_bundles").Child("sys").Attr("node_tag_filter")),
+					gomock.Eq("sys_nodes"),
+					gomock.Nil()).
+				Return(nil),
+			mockYtClient.EXPECT().
+				ListNode(
+					gomock.Any(),
+					gomock.Eq(ypath.Path("//sys/tablet_cells")),
+					gomock.Any(),
+					gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ ypath.YPath, result interface{}, _ *yt.ListNodeOptions) error {
+					*result.(*[]CypressObjectSnapshot) = []CypressObjectSnapshot{
+						{Name: "3-3-3-3", Attributes: cellAttributes},
+					}
+					return nil
+				}),
+			mockYtClient.EXPECT().
+				CreateObject(
+					gomock.Any(),
+					gomock.Eq(yt.NodeType("tablet_cell")),
+					gomock.Eq(&yt.CreateObjectOptions{Attributes: cellAttributes})).
+				Return(cellID, nil).
+				Times(1),
+			mockYtClient.EXPECT().
+				SetNode(
+					gomock.Any(),
+					gomock.Eq(ypath.Path("//sys/tablet_cells").Child(cellID.String()).Attr("peers").Child("0").Child("address")),
+					gomock.Eq("tnd-0.tablet-nodes:9022"),
+					gomock.Nil()).
+				Return(nil),
+		)
+
+		Expect(RestoreTabletCellsSnapshot(context.Background(), mockYtClient, snapshot)).Should(Succeed())
+	})
+})
@@ -34,6 +34,7 @@ func NewTabletNode(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
 	ytsaurusClient YtsaurusClient,
+	dependencies DependencyChecker,
 	spec ytv1.TabletNodesSpec,
 	doInitiailization bool,
 ) Component {
@@ -61,9 +62,10 @@ func NewTabletNode(
 
 	return &tabletNode{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server:               server,
 		initBundlesCondition: "bundlesTabletNodeInitCompleted",
@@ -77,15 +79,27 @@ func (tn *tabletNode) IsUpdatable() bool {
 	return true
 }
 
+func (tn *tabletNode) IsManaged() bool {
+	return tn.server.isManaged()
+}
+
+func (tn *tabletNode) GetPodsStatus() PodsStatus {
+	return tn.server.getPodsStatus()
+}
+
 func (tn *tabletNode) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 	logger := log.FromContext(ctx)
 
+	if !tn.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(tn.ytsaurus.GetClusterState()) && tn.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedFullUpdate), err
+		return getFullUpdateStatus(tn.server), err
 	}
 
-	if tn.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(tn.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, tn.ytsaurus, tn, &tn.componentBase, tn.server, dry); status != nil {
 			return *status, err
 		}
@@ -103,12 +117,24 @@ func (tn *tabletNode) doSync(ctx context.Context, dry bool) (ComponentStatus, er
 		return WaitingStatus(SyncStatusBlocked, "pods"), err
 	}
 
+	if tn.spec.WaitForOnlineState {
+		getAddress := func(ordinal int32) string {
+			return tn.cfgen.GetTabletNodeAddress(tn.spec.Name, ordinal)
+		}
+		if status := tn.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+			return *status, err
+		}
+		if status, err := getOnlineNodesStatus(ctx, tn.ytsaurus, tn.ytsaurusClient.GetYtClient(), &tn.spec.InstanceSpec, getAddress); status != nil {
+			return *status, err
+		}
+	}
+
 	if !tn.doInitialization || tn.ytsaurus.IsStatusConditionTrue(tn.initBundlesCondition) {
 		return SimpleStatus(SyncStatusReady), err
 	}
 
-	if tn.ytsaurusClient.Status(ctx).SyncStatus != SyncStatusReady {
-		return WaitingStatus(SyncStatusBlocked, tn.ytsaurusClient.GetName()), err
+	if status := tn.waitForDependencies(ctx, consts.YTComponentLabelClient); status != nil {
+		return *status, err
 	}
 
 	ytClient := tn.ytsaurusClient.GetYtClient()
@@ -210,13 +236,8 @@ func (tn *tabletNode) getBundleBootstrap(bundle string) *ytv1.BundleBootstrapSpe
 	return nil
 }
 
-func (tn *tabletNode) Status(ctx context.Context) ComponentStatus {
-	status, err := tn.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (tn *tabletNode) Status(ctx context.Context) (ComponentStatus, error) {
+	return tn.doSync(ctx, true)
 }
 
 func (tn *tabletNode) Sync(ctx context.Context) error {
@@ -165,13 +165,27 @@ var _ = Describe("Tablet node test", func() {
 
 			ytsaurusClient.SetStatus(SimpleStatus(SyncStatusPending))
 
-			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
+			dependencies := NewFakeDependencyChecker(ytsaurusClient)
+			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, dependencies, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
 			tabletNode.server = NewFakeServer()
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusBlocked))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusBlocked))
 
 			ytsaurusClient.SetStatus(SimpleStatus(SyncStatusReady))
 
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
+		})
+
+		It("Tablet node Sync; ytclient status evaluation failed", func() {
+			cfgen := ytconfig.NewGenerator(ytsaurusSpec, "cluster_domain")
+			ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(1), scheme)
+
+			ytsaurusClient := NewFakeYtsaurusClient(mockYtClient)
+			ytsaurusClient.SetStatusError(net.UnknownNetworkError("get: some net error"))
+
+			dependencies := NewFakeDependencyChecker(ytsaurusClient)
+			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, dependencies, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
+			tabletNode.server = NewFakeServer()
+			Expect(getStatus(tabletNode)).Should(Equal(WaitingStatus(SyncStatusBlocked, ytsaurusClient.GetName())))
 		})
 
 		It("Tablet node Sync; pods are not ready", func() {
@@ -179,16 +193,17 @@ var _ = Describe("Tablet node test", func() {
 			ytsaurus := apiproxy.NewYtsaurus(ytsaurusSpec, client, record.NewFakeRecorder(1), scheme)
 
 			ytsaurusClient := NewFakeYtsaurusClient(mockYtClient)
-			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
+			dependencies := NewFakeDependencyChecker(ytsaurusClient)
+			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, dependencies, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
 			fakeServer := NewFakeServer()
 			fakeServer.podsReady = false
 			tabletNode.server = fakeServer
 
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusBlocked))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusBlocked))
 
 			fakeServer.podsReady = true
 
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
 		})
 
 		It("Tablet node Sync; yt errors", func() {
@@ -316,38 +331,39 @@ var _ = Describe("Tablet node test", func() {
 					Return(yt.NodeID(guid.New()), nil).Times(1),
 			)
 
-			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
+			dependencies := NewFakeDependencyChecker(ytsaurusClient)
+			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, dependencies, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
 			tabletNode.server = NewFakeServer()
 
 			// Failed to check if there is //sys/tablet_cell_bundles/sys.
 			err := tabletNode.Sync(context.Background())
 			Expect(err).Should(Equal(existsNetError))
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
 
 			// Failed to create `sys` bundle.
 			err = tabletNode.Sync(context.Background())
 			Expect(err).Should(Equal(createBundleNetError))
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
 
 			// Failed to get @tablet_cell_count of the `sys` bundle.
 			err = tabletNode.Sync(context.Background())
 			Expect(err).Should(Equal(getNetError))
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
 
 			// Failed to create tablet_cell in the `sys` bundle.
 			err = tabletNode.Sync(context.Background())
 			Expect(err).Should(Equal(createCellNetError))
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
 
 			// Failed to get @tablet_cell_count of the `default` bundle.
 			err = tabletNode.Sync(context.Background())
 			Expect(err).Should(Equal(getNetError))
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusPending))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusPending))
 
 			// Then everything was successfully.
 			err = tabletNode.Sync(context.Background())
 			Expect(err).Should(Succeed())
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusReady))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusReady))
 		})
 
 		It("Tablet node Sync; success", func() {
@@ -397,12 +413,13 @@ var _ = Describe("Tablet node test", func() {
 							}})).Return(yt.NodeID(guid.New()), nil)
 			}
 
-			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
+			dependencies := NewFakeDependencyChecker(ytsaurusClient)
+			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, dependencies, ytsaurusSpec.Spec.TabletNodes[0], true).(*tabletNode)
 			tabletNode.server = NewFakeServer()
 			err := tabletNode.Sync(context.Background())
 			Expect(err).Should(Succeed())
 
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusReady))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusReady))
 		})
 
 		It("Tablet node Sync; no initialization", func() {
@@ -411,12 +428,13 @@ var _ = Describe("Tablet node test", func() {
 
 			ytsaurusClient := NewFakeYtsaurusClient(mockYtClient)
 
-			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, ytsaurusSpec.Spec.TabletNodes[0], false).(*tabletNode)
+			dependencies := NewFakeDependencyChecker(ytsaurusClient)
+			tabletNode := NewTabletNode(cfgen, ytsaurus, ytsaurusClient, dependencies, ytsaurusSpec.Spec.TabletNodes[0], false).(*tabletNode)
 			tabletNode.server = NewFakeServer()
 			err := tabletNode.Sync(context.Background())
 			Expect(err).Should(Succeed())
 
-			Expect(tabletNode.Status(context.Background()).SyncStatus).Should(Equal(SyncStatusReady))
+			Expect(getStatus(tabletNode).SyncStatus).Should(Equal(SyncStatusReady))
 		})
 	})
 })
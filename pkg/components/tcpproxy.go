@@ -16,8 +16,6 @@ type tcpProxy struct {
 	componentBase
 	server server
 
-	master Component
-
 	serviceType      *v1.ServiceType
 	balancingService *resources.TCPService
 }
@@ -25,7 +23,7 @@ type tcpProxy struct {
 func NewTCPProxy(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	masterReconciler Component,
+	dependencies DependencyChecker,
 	spec ytv1.TCPProxiesSpec) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
@@ -62,12 +60,12 @@ func NewTCPProxy(
 
 	return &tcpProxy{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server:           server,
-		master:           masterReconciler,
 		serviceType:      spec.ServiceType,
 		balancingService: balancingService,
 	}
@@ -77,6 +75,14 @@ func (tp *tcpProxy) IsUpdatable() bool {
 	return true
 }
 
+func (tp *tcpProxy) IsManaged() bool {
+	return tp.server.isManaged()
+}
+
+func (tp *tcpProxy) GetPodsStatus() PodsStatus {
+	return tp.server.getPodsStatus()
+}
+
 func (tp *tcpProxy) Fetch(ctx context.Context) error {
 	fetchable := []resources.Fetchable{
 		tp.server,
@@ -90,18 +96,22 @@ func (tp *tcpProxy) Fetch(ctx context.Context) error {
 func (tp *tcpProxy) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !tp.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(tp.ytsaurus.GetClusterState()) && tp.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, tp.server.getUpdateReason()), err
 	}
 
-	if tp.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(tp.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, tp.ytsaurus, tp, &tp.componentBase, tp.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(tp.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, tp.master.GetName()), err
+	if status := tp.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if tp.server.needSync() {
@@ -126,13 +136,8 @@ func (tp *tcpProxy) doSync(ctx context.Context, dry bool) (ComponentStatus, erro
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (tp *tcpProxy) Status(ctx context.Context) ComponentStatus {
-	status, err := tp.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (tp *tcpProxy) Status(ctx context.Context) (ComponentStatus, error) {
+	return tp.doSync(ctx, true)
 }
 
 func (tp *tcpProxy) Sync(ctx context.Context) error {
@@ -21,7 +21,6 @@ type UI struct {
 	componentBase
 	microservice microservice
 	initJob      *InitJob
-	master       Component
 	secret       *resources.StringSecret
 }
 
@@ -31,7 +30,7 @@ const UICustomConfigFileName = "common.js"
 func NewUI(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	master Component,
+	dependencies DependencyChecker,
 ) Component {
 	res := ytsaurus.GetResource()
 	l := labeller.Labeller{
@@ -76,9 +75,10 @@ func NewUI(
 
 	return &UI{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		microservice: svc,
 		initJob: NewInitJob(
@@ -97,7 +97,6 @@ func NewUI(
 			&l,
 			ytsaurus.APIProxy(),
 		),
-		master: master,
 	}
 }
 
@@ -105,6 +104,10 @@ func (u *UI) IsUpdatable() bool {
 	return true
 }
 
+func (u *UI) GetPodsStatus() PodsStatus {
+	return u.microservice.getPodsStatus()
+}
+
 func (u *UI) Fetch(ctx context.Context) error {
 
 	return resources.Fetch(ctx,
@@ -253,10 +256,10 @@ func (u *UI) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
 	if u.ytsaurus.GetClusterState() == ytv1.ClusterStateRunning && u.microservice.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, u.microservice.getUpdateReason()), err
 	}
 
-	if u.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(u.ytsaurus.GetClusterState()) {
 		if IsUpdatingComponent(u.ytsaurus, u) {
 			if u.ytsaurus.GetUpdateState() == ytv1.UpdateStateWaitingForPodsRemoval {
 				if !dry {
@@ -273,8 +276,8 @@ func (u *UI) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 		}
 	}
 
-	if !IsRunningStatus(u.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, u.master.GetName()), err
+	if status := u.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if u.secret.NeedSync(consts.TokenSecretKey, "") {
@@ -312,13 +315,8 @@ func (u *UI) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (u *UI) Status(ctx context.Context) ComponentStatus {
-	status, err := u.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (u *UI) Status(ctx context.Context) (ComponentStatus, error) {
+	return u.doSync(ctx, true)
 }
 
 func (u *UI) Sync(ctx context.Context) error {
@@ -0,0 +1,183 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+)
+
+// UpdateStep is a step of the update flow, the flow is at the step while the update state equals to the step state.
+type UpdateStep struct {
+	State ytv1.UpdateState
+	// Message is logged when the flow enters the step.
+	Message string
+	// ShouldRun is the entry condition, the step is skipped if it returns false. Nil means that the step always runs.
+	ShouldRun func() bool
+	// Action is called on every reconciliation while the flow is at the step.
+	Action func(ctx context.Context) error
+	// IsDone is the completion condition, the flow goes to the next step when it returns true.
+	IsDone func() bool
+	// IsFailed is checked before the completion condition, the flow goes to its failure step when it returns true.
+	IsFailed func() bool
+	// RequeueAfter delays the next reconciliation after the step is completed.
+	RequeueAfter time.Duration
+}
+
+func (s *UpdateStep) shouldRun() bool {
+	return s.ShouldRun == nil || s.ShouldRun()
+}
+
+func (s *UpdateStep) isDone() bool {
+	return s.IsDone == nil || s.IsDone()
+}
+
+func (s *UpdateStep) isFailed() bool {
+	return s.IsFailed != nil && s.IsFailed()
+}
+
+// UpdateStepsProvider is implemented by components which contribute their own steps to the update flow.
+type UpdateStepsProvider interface {
+	GetUpdateSteps() []UpdateStep
+}
+
+// UpdateFlow runs the update as a sequence of steps. When all the steps are done
+// the update is finished or canceled if it's rolled back.
+type UpdateFlow struct {
+	steps       []UpdateStep
+	failureStep *UpdateStep
+	isRollback  func() bool
+	finish      func(ctx context.Context) error
+}
+
+func NewUpdateFlow(steps ...UpdateStep) *UpdateFlow {
+	return &UpdateFlow{steps: steps}
+}
+
+// WithFailureStep sets the step the flow goes to when one of the steps fails.
+// The update is canceled when the failure step is done.
+func (f *UpdateFlow) WithFailureStep(step UpdateStep) *UpdateFlow {
+	f.failureStep = &step
+	return f
+}
+
+// WithRollback sets the condition under which the update is canceled instead of finished at the end of the flow.
+func (f *UpdateFlow) WithRollback(isRollback func() bool) *UpdateFlow {
+	f.isRollback = isRollback
+	return f
+}
+
+// WithFinish sets the action run at the end of the flow instead of moving the cluster to the update finishing state.
+func (f *UpdateFlow) WithFinish(finish func(ctx context.Context) error) *UpdateFlow {
+	f.finish = finish
+	return f
+}
+
+// GetStates returns the states of all the steps of the flow including the skipped ones.
+func (f *UpdateFlow) GetStates() []ytv1.UpdateState {
+	states := make([]ytv1.UpdateState, 0, len(f.steps))
+	for _, step := range f.steps {
+		states = append(states, step.State)
+	}
+	return states
+}
+
+func (f *UpdateFlow) findStep(state ytv1.UpdateState) int {
+	for i, step := range f.steps {
+		if step.State == state {
+			return i
+		}
+	}
+	return -1
+}
+
+// Advance runs the action of the current step and moves the flow further when the step is done.
+// Nil result means that the flow stays at the current step.
+func (f *UpdateFlow) Advance(ctx context.Context, ytsaurus *apiproxy.Ytsaurus) (*reconcile.Result, error) {
+	state := ytsaurus.GetUpdateState()
+
+	if f.failureStep != nil && state == f.failureStep.State {
+		return f.advanceFailureStep(ctx, ytsaurus)
+	}
+
+	next := 0
+	var requeueAfter time.Duration
+	if state != ytv1.UpdateStateNone && state != "" {
+		current := f.findStep(state)
+		if current < 0 {
+			return &reconcile.Result{}, fmt.Errorf("update state %s doesn't belong to the update flow", state)
+		}
+
+		step := &f.steps[current]
+		if step.Action != nil {
+			if err := step.Action(ctx); err != nil {
+				return &reconcile.Result{Requeue: true}, err
+			}
+		}
+
+		if f.failureStep != nil && step.isFailed() {
+			ytsaurus.LogUpdate(ctx, f.failureStep.Message)
+			err := ytsaurus.SaveUpdateState(ctx, f.failureStep.State)
+			return &reconcile.Result{Requeue: true}, err
+		}
+
+		if !step.isDone() {
+			return nil, nil
+		}
+		next = current + 1
+		requeueAfter = step.RequeueAfter
+	}
+
+	for ; next < len(f.steps); next++ {
+		step := &f.steps[next]
+		if step.shouldRun() {
+			ytsaurus.LogUpdate(ctx, step.Message)
+			err := ytsaurus.SaveUpdateState(ctx, step.State)
+			return getStepResult(requeueAfter), err
+		}
+		ytsaurus.LogUpdate(ctx, fmt.Sprintf("Update step %s was skipped", step.State))
+	}
+
+	if f.isRollback != nil && f.isRollback() {
+		ytsaurus.LogUpdate(ctx, "Update was rolled back")
+		err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateCancelUpdate)
+		return &reconcile.Result{Requeue: true}, err
+	}
+
+	if f.finish != nil {
+		err := f.finish(ctx)
+		return getStepResult(requeueAfter), err
+	}
+
+	ytsaurus.LogUpdate(ctx, "Finishing")
+	err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateUpdateFinishing)
+	return getStepResult(requeueAfter), err
+}
+
+func (f *UpdateFlow) advanceFailureStep(ctx context.Context, ytsaurus *apiproxy.Ytsaurus) (*reconcile.Result, error) {
+	step := f.failureStep
+	if step.Action != nil {
+		if err := step.Action(ctx); err != nil {
+			return &reconcile.Result{Requeue: true}, err
+		}
+	}
+
+	if !step.isDone() {
+		return nil, nil
+	}
+
+	ytsaurus.LogUpdate(ctx, "Update is canceling")
+	err := ytsaurus.SaveClusterState(ctx, ytv1.ClusterStateCancelUpdate)
+	return &reconcile.Result{Requeue: true}, err
+}
+
+func getStepResult(requeueAfter time.Duration) *reconcile.Result {
+	if requeueAfter > 0 {
+		return &reconcile.Result{RequeueAfter: requeueAfter}
+	}
+	return &reconcile.Result{Requeue: true}
+}
@@ -0,0 +1,144 @@
+package components
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+)
+
+var _ = Describe("Update flow test", func() {
+	var ytsaurus *apiproxy.Ytsaurus
+	var done map[v1.UpdateState]bool
+	var actions []v1.UpdateState
+
+	step := func(state v1.UpdateState) UpdateStep {
+		return UpdateStep{
+			State:   state,
+			Message: string(state),
+			Action: func(context.Context) error {
+				actions = append(actions, state)
+				return nil
+			},
+			IsDone: func() bool { return done[state] },
+		}
+	}
+
+	BeforeEach(func() {
+		resource := &v1.Ytsaurus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ytsaurus",
+				Namespace: "default",
+			},
+			Status: v1.YtsaurusStatus{
+				State: v1.ClusterStateUpdating,
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resource).Build()
+		ytsaurus = apiproxy.NewYtsaurus(resource, client, record.NewFakeRecorder(100), scheme)
+
+		done = make(map[v1.UpdateState]bool)
+		actions = nil
+	})
+
+	It("Steps are run in order", func() {
+		ctx := context.Background()
+		skipped := step(v1.UpdateStateWaitingForSafeModeEnabled)
+		skipped.ShouldRun = func() bool { return false }
+		flow := NewUpdateFlow(
+			step(v1.UpdateStateWaitingForPodsRemoval),
+			skipped,
+			step(v1.UpdateStateWaitingForPodsCreation),
+		)
+		Expect(flow.GetStates()).Should(Equal([]v1.UpdateState{
+			v1.UpdateStateWaitingForPodsRemoval,
+			v1.UpdateStateWaitingForSafeModeEnabled,
+			v1.UpdateStateWaitingForPodsCreation,
+		}))
+
+		result, err := flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(result).ShouldNot(BeNil())
+		Expect(ytsaurus.GetUpdateState()).Should(Equal(v1.UpdateStateWaitingForPodsRemoval))
+
+		result, err = flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(result).Should(BeNil())
+		Expect(ytsaurus.GetUpdateState()).Should(Equal(v1.UpdateStateWaitingForPodsRemoval))
+
+		done[v1.UpdateStateWaitingForPodsRemoval] = true
+		_, err = flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetUpdateState()).Should(Equal(v1.UpdateStateWaitingForPodsCreation))
+
+		done[v1.UpdateStateWaitingForPodsCreation] = true
+		_, err = flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetClusterState()).Should(Equal(v1.ClusterStateUpdateFinishing))
+
+		Expect(actions).Should(Equal([]v1.UpdateState{
+			v1.UpdateStateWaitingForPodsRemoval,
+			v1.UpdateStateWaitingForPodsRemoval,
+			v1.UpdateStateWaitingForPodsCreation,
+		}))
+	})
+
+	It("Failed step leads to cancellation", func() {
+		ctx := context.Background()
+		failed := false
+		check := step(v1.UpdateStatePossibilityCheck)
+		check.IsFailed = func() bool { return failed }
+		flow := NewUpdateFlow(check, step(v1.UpdateStateWaitingForSafeModeEnabled)).
+			WithFailureStep(step(v1.UpdateStateImpossibleToStart))
+
+		_, err := flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetUpdateState()).Should(Equal(v1.UpdateStatePossibilityCheck))
+
+		failed = true
+		_, err = flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetUpdateState()).Should(Equal(v1.UpdateStateImpossibleToStart))
+
+		result, err := flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(result).Should(BeNil())
+		Expect(ytsaurus.GetClusterState()).Should(Equal(v1.ClusterStateUpdating))
+
+		done[v1.UpdateStateImpossibleToStart] = true
+		_, err = flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetClusterState()).Should(Equal(v1.ClusterStateCancelUpdate))
+	})
+
+	It("Rolled back update is canceled", func() {
+		ctx := context.Background()
+		flow := NewUpdateFlow(step(v1.UpdateStateWaitingForPodsCreation)).
+			WithRollback(func() bool { return true })
+		Expect(ytsaurus.SaveUpdateState(ctx, v1.UpdateStateWaitingForPodsCreation)).Should(Succeed())
+
+		done[v1.UpdateStateWaitingForPodsCreation] = true
+		_, err := flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(Succeed())
+		Expect(ytsaurus.GetClusterState()).Should(Equal(v1.ClusterStateCancelUpdate))
+	})
+
+	It("Unknown state is reported", func() {
+		ctx := context.Background()
+		flow := NewUpdateFlow(step(v1.UpdateStateWaitingForPodsRemoval))
+		Expect(ytsaurus.SaveUpdateState(ctx, v1.UpdateStateWaitingForSnapshots)).Should(Succeed())
+
+		_, err := flow.Advance(ctx, ytsaurus)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -19,12 +19,11 @@ import (
 type yqlAgent struct {
 	componentBase
 	server          server
-	master          Component
 	initEnvironment *InitJob
 	secret          *resources.StringSecret
 }
 
-func NewYQLAgent(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus, master Component) Component {
+func NewYQLAgent(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus, dependencies DependencyChecker) Component {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
 		ObjectMeta:     &resource.ObjectMeta,
@@ -49,12 +48,12 @@ func NewYQLAgent(cfgen *ytconfig.Generator, ytsaurus *apiproxy.Ytsaurus, master
 
 	return &yqlAgent{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
 		server: svc,
-		master: master,
 		initEnvironment: NewInitJob(
 			&l,
 			ytsaurus.GetJobs(),
@@ -78,6 +77,14 @@ func (yqla *yqlAgent) IsUpdatable() bool {
 	return true
 }
 
+func (yqla *yqlAgent) IsManaged() bool {
+	return yqla.server.isManaged()
+}
+
+func (yqla *yqlAgent) GetPodsStatus() PodsStatus {
+	return yqla.server.getPodsStatus()
+}
+
 func (yqla *yqlAgent) GetName() string {
 	return yqla.labeller.ComponentName
 }
@@ -122,18 +129,22 @@ func (yqla *yqlAgent) createInitScript() string {
 func (yqla *yqlAgent) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
 
+	if !yqla.IsManaged() {
+		return SimpleStatus(SyncStatusUnmanaged), err
+	}
+
 	if ytv1.IsReadyToUpdateClusterState(yqla.ytsaurus.GetClusterState()) && yqla.server.needUpdate() {
-		return SimpleStatus(SyncStatusNeedLocalUpdate), err
+		return NewComponentStatus(SyncStatusNeedLocalUpdate, yqla.server.getUpdateReason()), err
 	}
 
-	if yqla.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(yqla.ytsaurus.GetClusterState()) {
 		if status, err := handleUpdatingClusterState(ctx, yqla.ytsaurus, yqla, &yqla.componentBase, yqla.server, dry); status != nil {
 			return *status, err
 		}
 	}
 
-	if !IsRunningStatus(yqla.master.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, yqla.master.GetName()), err
+	if status := yqla.waitForDependencies(ctx, consts.YTComponentLabelMaster); status != nil {
+		return *status, err
 	}
 
 	if yqla.secret.NeedSync(consts.TokenSecretKey, "") {
@@ -175,13 +186,8 @@ func (yqla *yqlAgent) doSync(ctx context.Context, dry bool) (ComponentStatus, er
 	return yqla.initEnvironment.Sync(ctx, dry)
 }
 
-func (yqla *yqlAgent) Status(ctx context.Context) ComponentStatus {
-	status, err := yqla.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (yqla *yqlAgent) Status(ctx context.Context) (ComponentStatus, error) {
+	return yqla.doSync(ctx, true)
 }
 
 func (yqla *yqlAgent) Sync(ctx context.Context) error {
@@ -17,6 +17,7 @@ import (
 	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/consts"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/metrics"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/resources"
 	"github.com/ytsaurus/yt-k8s-operator/pkg/ytconfig"
 )
@@ -24,22 +25,23 @@ import (
 type YtsaurusClient interface {
 	Component
 	GetYtClient() yt.Client
+	CheckHealth(ctx context.Context) ([]string, error)
 }
 
 type ytsaurusClient struct {
 	componentBase
-	httpProxy Component
 
 	initUserJob *InitJob
 
-	secret   *resources.StringSecret
-	ytClient yt.Client
+	secret              *resources.StringSecret
+	tabletCellsSnapshot *resources.ConfigMap
+	ytClient            yt.Client
 }
 
 func NewYtsaurusClient(
 	cfgen *ytconfig.Generator,
 	ytsaurus *apiproxy.Ytsaurus,
-	httpProxy Component,
+	dependencies DependencyChecker,
 ) YtsaurusClient {
 	resource := ytsaurus.GetResource()
 	l := labeller.Labeller{
@@ -53,11 +55,11 @@ func NewYtsaurusClient(
 
 	return &ytsaurusClient{
 		componentBase: componentBase{
-			labeller: &l,
-			ytsaurus: ytsaurus,
-			cfgen:    cfgen,
+			labeller:     &l,
+			ytsaurus:     ytsaurus,
+			cfgen:        cfgen,
+			dependencies: dependencies,
 		},
-		httpProxy: httpProxy,
 		initUserJob: NewInitJob(
 			&l,
 			ytsaurus.GetJobs(),
@@ -72,6 +74,10 @@ func NewYtsaurusClient(
 			l.GetSecretName(),
 			&l,
 			ytsaurus.APIProxy()),
+		tabletCellsSnapshot: resources.NewConfigMap(
+			l.GetTabletCellsSnapshotConfigMapName(),
+			&l,
+			ytsaurus.APIProxy()),
 	}
 }
 
@@ -82,8 +88,8 @@ func (yc *ytsaurusClient) IsUpdatable() bool {
 func (yc *ytsaurusClient) Fetch(ctx context.Context) error {
 	return resources.Fetch(ctx,
 		yc.secret,
+		yc.tabletCellsSnapshot,
 		yc.initUserJob,
-		yc.httpProxy,
 	)
 }
 
@@ -103,6 +109,24 @@ type MasterInfo struct {
 	Addresses []string `yson:"addresses" json:"addresses"`
 }
 
+// GetYtClientConfig returns the config of the operator client of the cluster,
+// YTOP_PROXY environment variable overrides the address of the http proxies.
+func GetYtClientConfig(cfgen *ytconfig.Generator, token string) *yt.Config {
+	timeout := time.Second * 10
+	proxy, ok := os.LookupEnv("YTOP_PROXY")
+	disableProxyDiscovery := true
+	if !ok {
+		proxy = cfgen.GetHTTPProxiesAddress(consts.DefaultHTTPProxyRole)
+		disableProxyDiscovery = false
+	}
+	return &yt.Config{
+		Proxy:                 proxy,
+		Token:                 token,
+		LightRequestTimeout:   &timeout,
+		DisableProxyDiscovery: disableProxyDiscovery,
+	}
+}
+
 func getReadOnlyGetOptions() *yt.GetNodeOptions {
 	return &yt.GetNodeOptions{
 		TransactionOptions: &yt.TransactionOptions{
@@ -124,6 +148,8 @@ type MasterHydra struct {
 	LastSnapshotReadOnly bool        `yson:"last_snapshot_read_only"`
 	Active               bool        `yson:"active"`
 	State                MasterState `yson:"state"`
+	// Sequence number of the last mutation applied by the peer.
+	SequenceNumber int64 `yson:"sequence_number"`
 }
 
 func (yc *ytsaurusClient) getAllMasters(ctx context.Context) ([]MasterInfo, error) {
@@ -150,6 +176,28 @@ func (yc *ytsaurusClient) getMasterHydra(ctx context.Context, path string) (Mast
 	return masterHydra, err
 }
 
+// GetMasterCellHydra returns the hydra states of the master cell peers by their addresses,
+// cellPath is //sys/primary_masters or a cell tag child of //sys/secondary_masters.
+// The state of an unavailable peer is nil.
+func GetMasterCellHydra(ctx context.Context, ytClient yt.Client, cellPath ypath.Path) (map[string]*MasterHydra, error) {
+	var addresses []string
+	if err := ytClient.ListNode(ctx, cellPath, &addresses, nil); err != nil {
+		return nil, fmt.Errorf("list peers of master cell %s: %w", cellPath, err)
+	}
+
+	peers := make(map[string]*MasterHydra, len(addresses))
+	for _, address := range addresses {
+		var hydra MasterHydra
+		err := ytClient.GetNode(ctx, cellPath.JoinChild(address, "orchid", "monitoring", "hydra"), &hydra, getReadOnlyGetOptions())
+		if err != nil {
+			peers[address] = nil
+			continue
+		}
+		peers[address] = &hydra
+	}
+	return peers, nil
+}
+
 func (yc *ytsaurusClient) startBuildMasterSnapshots(ctx context.Context) error {
 	var err error
 
@@ -196,163 +244,8 @@ func (yc *ytsaurusClient) handleUpdatingState(ctx context.Context) (ComponentSta
 				return SimpleStatus(SyncStatusUpdating), nil
 			}
 
-			// Check tablet cell bundles.
-			notGoodBundles, err := GetNotGoodTabletCellBundles(ctx, yc.ytClient)
-			if err != nil {
-				return SimpleStatus(SyncStatusUpdating), err
-			}
-
-			if len(notGoodBundles) > 0 {
-				yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
-					Type:    consts.ConditionNoPossibility,
-					Status:  metav1.ConditionTrue,
-					Reason:  "Update",
-					Message: fmt.Sprintf("Tablet cell bundles (%v) aren't in 'good' health", notGoodBundles),
-				})
-				return SimpleStatus(SyncStatusUpdating), nil
-			}
-
-			// Check LVC.
-			lvcCount := 0
-			err = yc.ytClient.GetNode(ctx, ypath.Path("//sys/lost_vital_chunks/@count"), &lvcCount, nil)
-			if err != nil {
-				return SimpleStatus(SyncStatusUpdating), err
-			}
-
-			if lvcCount > 0 {
-				yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
-					Type:    consts.ConditionNoPossibility,
-					Status:  metav1.ConditionTrue,
-					Reason:  "Update",
-					Message: fmt.Sprintf("There are lost vital chunks: %v", lvcCount),
-				})
-				return SimpleStatus(SyncStatusUpdating), nil
-			}
-
-			// Check QMC.
-			qmcCount := 0
-			err = yc.ytClient.GetNode(ctx, ypath.Path("//sys/quorum_missing_chunks/@count"), &qmcCount, nil)
-			if err != nil {
-				return SimpleStatus(SyncStatusUpdating), err
-			}
-
-			if qmcCount > 0 {
-				yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
-					Type:    consts.ConditionNoPossibility,
-					Status:  metav1.ConditionTrue,
-					Reason:  "Update",
-					Message: fmt.Sprintf("There are quorum missing chunks: %v", qmcCount),
-				})
-				return SimpleStatus(SyncStatusUpdating), nil
-			}
-
-			type checkResult struct {
-				Possible bool
-				Message  string
-			}
-
-			checkMasters := func(msgContext string, addrsPath ypath.Path) (cond checkResult, _ error) {
-				addrs := make([]string, 0)
-				err = yc.ytClient.ListNode(ctx, addrsPath, &addrs, nil)
-				if err != nil {
-					return cond, err
-				}
-
-				leadingCount := 0
-				followingCount := 0
-
-				for _, addr := range addrs {
-					var hydra MasterHydra
-					err := yc.ytClient.GetNode(
-						ctx,
-						ypath.Path(addrsPath.JoinChild(addr, "orchid", "monitoring", "hydra")),
-						&hydra,
-						nil)
-					if err != nil {
-						return cond, err
-					}
-
-					if !hydra.Active {
-						return checkResult{
-							Possible: false,
-							Message:  fmt.Sprintf("There is a non-active %s: %v", msgContext, addrs),
-						}, nil
-					}
-
-					switch hydra.State {
-					case MasterStateLeading:
-						leadingCount += 1
-					case MasterStateFollowing:
-						followingCount += 1
-					}
-				}
-
-				if !(leadingCount == 1 && followingCount+1 == len(addrs)) {
-					return checkResult{
-						Possible: false,
-						Message:  fmt.Sprintf("There is no %s leader or some peer is not active", msgContext),
-					}, nil
-				}
-
-				return checkResult{
-					Possible: true,
-					Message:  "Update is possible",
-				}, nil
-			}
-
-			// Check primary masters.
-			cond, err := checkMasters(
-				"primary master",
-				ypath.Path(`//sys/primary_masters`),
-			)
-			if err != nil {
-				return SimpleStatus(SyncStatusUpdating), err
-			}
-			if !cond.Possible {
-				yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
-					Type:    consts.ConditionNoPossibility,
-					Status:  metav1.ConditionTrue,
-					Reason:  "Update",
-					Message: cond.Message,
-				})
-				return SimpleStatus(SyncStatusUpdating), nil
-			}
-
-			if len(yc.ytsaurus.GetResource().Spec.SecondaryMasters) > 0 {
-				var (
-					cellTags         = make([]string, 0)
-					secondaryMasters = ypath.Path(`//sys/secondary_masters`)
-				)
-				if err := yc.ytClient.ListNode(ctx, secondaryMasters, &cellTags, nil); err != nil {
-					return SimpleStatus(SyncStatusUpdating), err
-				}
-				for _, cellTag := range cellTags {
-					cond, err := checkMasters(
-						fmt.Sprintf("secondary master (cell_tag: %q)", cellTag),
-						secondaryMasters.Child(cellTag),
-					)
-					if err != nil {
-						return SimpleStatus(SyncStatusUpdating), err
-					}
-					if !cond.Possible {
-						yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
-							Type:    consts.ConditionNoPossibility,
-							Status:  metav1.ConditionTrue,
-							Reason:  "Update",
-							Message: cond.Message,
-						})
-						return SimpleStatus(SyncStatusUpdating), nil
-					}
-				}
-			}
-
-			yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
-				Type:    consts.ConditionHasPossibility,
-				Status:  metav1.ConditionTrue,
-				Reason:  "Update",
-				Message: "Update is possible",
-			})
-			return SimpleStatus(SyncStatusUpdating), nil
+			err := yc.runPossibilityChecks(ctx)
+			return SimpleStatus(SyncStatusUpdating), err
 		}
 
 	case ytv1.UpdateStateWaitingForSafeModeEnabled:
@@ -386,6 +279,10 @@ func (yc *ytsaurusClient) handleUpdatingState(ctx context.Context) (ComponentSta
 
 			yc.ytsaurus.GetResource().Status.UpdateStatus.TabletCellBundles = tabletCellBundles
 
+			if err := yc.saveTabletCellsSnapshot(ctx); err != nil {
+				return SimpleStatus(SyncStatusUpdating), err
+			}
+
 			yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
 				Type:    consts.ConditionTabletCellsSaved,
 				Status:  metav1.ConditionTrue,
@@ -516,11 +413,22 @@ func (yc *ytsaurusClient) handleUpdatingState(ctx context.Context) (ComponentSta
 	case ytv1.UpdateStateWaitingForTabletCellsRecovery:
 		if !yc.ytsaurus.IsUpdateStatusConditionTrue(consts.ConditionTabletCellsRecovered) {
 
-			for _, bundle := range yc.ytsaurus.GetResource().Status.UpdateStatus.TabletCellBundles {
-				err = CreateTabletCells(ctx, yc.ytClient, bundle.Name, bundle.TabletCellCount)
-				if err != nil {
+			snapshot, err := yc.loadTabletCellsSnapshot()
+			if err != nil {
+				return SimpleStatus(SyncStatusUpdating), err
+			}
+
+			if snapshot != nil {
+				if err := RestoreTabletCellsSnapshot(ctx, yc.ytClient, snapshot); err != nil {
 					return SimpleStatus(SyncStatusUpdating), err
 				}
+			} else {
+				for _, bundle := range yc.ytsaurus.GetResource().Status.UpdateStatus.TabletCellBundles {
+					err = CreateTabletCells(ctx, yc.ytClient, bundle.Name, bundle.TabletCellCount)
+					if err != nil {
+						return SimpleStatus(SyncStatusUpdating), err
+					}
+				}
 			}
 
 			yc.ytsaurus.SetUpdateStatusCondition(ctx, metav1.Condition{
@@ -559,8 +467,8 @@ func (yc *ytsaurusClient) getToken() string {
 
 func (yc *ytsaurusClient) doSync(ctx context.Context, dry bool) (ComponentStatus, error) {
 	var err error
-	if !IsRunningStatus(yc.httpProxy.Status(ctx).SyncStatus) {
-		return WaitingStatus(SyncStatusBlocked, yc.httpProxy.GetName()), err
+	if status := yc.waitForDependencies(ctx, consts.YTComponentLabelHTTPProxy); status != nil {
+		return *status, err
 	}
 
 	if yc.secret.NeedSync(consts.TokenSecretKey, "") {
@@ -584,25 +492,14 @@ func (yc *ytsaurusClient) doSync(ctx context.Context, dry bool) (ComponentStatus
 
 	if yc.ytClient == nil {
 		token, _ := yc.secret.GetValue(consts.TokenSecretKey)
-		timeout := time.Second * 10
-		proxy, ok := os.LookupEnv("YTOP_PROXY")
-		disableProxyDiscovery := true
-		if !ok {
-			proxy = yc.cfgen.GetHTTPProxiesAddress(consts.DefaultHTTPProxyRole)
-			disableProxyDiscovery = false
-		}
-		yc.ytClient, err = ythttp.NewClient(&yt.Config{
-			Proxy:                 proxy,
-			Token:                 token,
-			LightRequestTimeout:   &timeout,
-			DisableProxyDiscovery: disableProxyDiscovery,
-		})
+		ytClient, err := ythttp.NewClient(GetYtClientConfig(yc.cfgen, token))
 		if err != nil {
 			return WaitingStatus(SyncStatusPending, "ytClient init"), err
 		}
+		yc.ytClient = metrics.NewInstrumentedClient(ytClient, yc.labeller.ObjectMeta.Namespace, yc.labeller.ObjectMeta.Name)
 	}
 
-	if yc.ytsaurus.GetClusterState() == ytv1.ClusterStateUpdating {
+	if ytv1.IsUpdatingClusterState(yc.ytsaurus.GetClusterState()) {
 		if yc.ytsaurus.GetResource().Status.UpdateStatus.State == ytv1.UpdateStateImpossibleToStart {
 			return SimpleStatus(SyncStatusReady), err
 		}
@@ -615,13 +512,8 @@ func (yc *ytsaurusClient) doSync(ctx context.Context, dry bool) (ComponentStatus
 	return SimpleStatus(SyncStatusReady), err
 }
 
-func (yc *ytsaurusClient) Status(ctx context.Context) ComponentStatus {
-	status, err := yc.doSync(ctx, true)
-	if err != nil {
-		panic(err)
-	}
-
-	return status
+func (yc *ytsaurusClient) Status(ctx context.Context) (ComponentStatus, error) {
+	return yc.doSync(ctx, true)
 }
 
 func (yc *ytsaurusClient) Sync(ctx context.Context) error {
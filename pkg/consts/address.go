@@ -3,6 +3,9 @@ package consts
 const YTMonitoringPortName = "ytsaurus-metrics"
 const YTMonitoringPort = 10000
 
+// OrchidServicePath is served by the monitoring HTTP server of all YTsaurus servers.
+const OrchidServicePath = "/orchid/service"
+
 const (
 	DiscoveryRPCPort        = 9020
 	DiscoveryMonitoringPort = 10020
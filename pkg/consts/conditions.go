@@ -18,3 +18,10 @@ const ConditionQTStatePreparedForUpdating = "QTStatePreparedForUpdating"
 const ConditionMasterExitReadOnlyPrepared = "MasterExitReadOnlyPrepared"
 const ConditionMasterExitedReadOnly = "MasterExitedReadOnly"
 const ConditionSafeModeDisabled = "SafeModeDisabled"
+const ConditionWaitingForApproval = "WaitingForApproval"
+const ConditionUpdateStuck = "UpdateStuck"
+const ConditionUpdateRollback = "UpdateRollback"
+const ConditionInvalidVersions = "InvalidVersions"
+const ConditionUpdatePending = "UpdatePending"
+const ConditionDegraded = "Degraded"
+const ConditionHealthy = "Healthy"
@@ -3,6 +3,13 @@ package consts
 const YTComponentLabelName = "yt_component"
 const YTMetricsLabelName = "yt_metrics"
 
+const ConfigHashAnnotationName = "ytsaurus.tech/config-hash"
+const ApprovedUpdateStateAnnotationName = "ytsaurus.tech/approved-update-state"
+const UpdatePlanOnlyAnnotationName = "ytsaurus.tech/update-plan-only"
+const RestartedAtAnnotationName = "ytsaurus.tech/restarted-at"
+
+const YtsaurusFinalizer = "cluster.ytsaurus.tech/teardown"
+
 const (
 	YTComponentLabelDiscovery       string = "yt-discovery"
 	YTComponentLabelMaster          string = "yt-master"
@@ -49,6 +49,10 @@ func (l *Labeller) GetMainConfigMapName() string {
 	return fmt.Sprintf("%s-config", l.ComponentLabel)
 }
 
+func (l *Labeller) GetTabletCellsSnapshotConfigMapName() string {
+	return fmt.Sprintf("%s-tablet-cells-snapshot", l.ComponentLabel)
+}
+
 func (l *Labeller) GetInitJobName(name string) string {
 	return fmt.Sprintf("%s-init-job-%s", l.ComponentLabel, strings.ToLower(name))
 }
@@ -135,3 +139,19 @@ func (l *Labeller) GetMonitoringMetaLabelMap() map[string]string {
 func GetPodsRemovedCondition(componentName string) string {
 	return fmt.Sprintf("%sPodsRemoved", componentName)
 }
+
+func GetPodsUpdatedCondition(componentName string) string {
+	return fmt.Sprintf("%sPodsUpdated", componentName)
+}
+
+func GetJobsDrainingStartedCondition(componentName string) string {
+	return fmt.Sprintf("%sJobsDrainingStarted", componentName)
+}
+
+func GetJobsDrainedCondition(componentName string) string {
+	return fmt.Sprintf("%sJobsDrained", componentName)
+}
+
+func GetSchedulerJobsEnabledCondition(componentName string) string {
+	return fmt.Sprintf("%sSchedulerJobsEnabled", componentName)
+}
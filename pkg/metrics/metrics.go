@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+)
+
+const namespace = "ytsaurus"
+
+var (
+	clusterState = newStateGauge(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_state",
+			Help:      "Current state of the Ytsaurus cluster, the series of the current state has value 1.",
+		},
+		[]string{"namespace", "name", "state"},
+	))
+
+	updateState = newStateGauge(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "update_state",
+			Help:      "Current update state of the Ytsaurus cluster, the series of the current state has value 1.",
+		},
+		[]string{"namespace", "name", "state"},
+	))
+
+	updateStateSeconds = newStateGauge(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "update_state_seconds",
+			Help:      "Time spent in the current update state.",
+		},
+		[]string{"namespace", "name", "state"},
+	))
+
+	updateStateDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "update_state_duration_seconds",
+			Help:      "Time spent in the finished update states.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{"namespace", "name", "state"},
+	)
+
+	componentSyncStatus = newStateGauge(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "component_sync_status",
+			Help:      "Current sync status of the component, the series of the current status has value 1.",
+		},
+		[]string{"namespace", "name", "component", "status"},
+	))
+
+	componentSyncErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "component_sync_errors_total",
+			Help:      "Number of failed syncs of the component.",
+		},
+		[]string{"namespace", "name", "component"},
+	)
+
+	initJobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "init_job_duration_seconds",
+			Help:      "Duration of the completed init jobs.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		},
+		[]string{"namespace", "name", "component", "job"},
+	)
+
+	initJobFailures = newStateGauge(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "init_job_failures",
+			Help:      "Number of failed pods of the current init job.",
+		},
+		[]string{"namespace", "name", "component", "job"},
+	))
+
+	ytClientRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "yt_client_request_duration_seconds",
+			Help:      "Latency of the requests to the YTsaurus cluster.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"namespace", "name", "method"},
+	)
+
+	ytClientRequestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "yt_client_request_errors_total",
+			Help:      "Number of failed requests to the YTsaurus cluster.",
+		},
+		[]string{"namespace", "name", "method"},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		clusterState.vec,
+		updateState.vec,
+		updateStateSeconds.vec,
+		updateStateDuration,
+		componentSyncStatus.vec,
+		componentSyncErrors,
+		initJobDuration,
+		initJobFailures.vec,
+		ytClientRequestDuration,
+		ytClientRequestErrors,
+	)
+}
+
+// stateGauge keeps a single series per key, e.g. only the series of the current state of the cluster.
+type stateGauge struct {
+	vec    *prometheus.GaugeVec
+	mutex  sync.Mutex
+	labels map[string][]string
+}
+
+func newStateGauge(vec *prometheus.GaugeVec) *stateGauge {
+	return &stateGauge{
+		vec:    vec,
+		labels: make(map[string][]string),
+	}
+}
+
+func getKey(labelValues ...string) string {
+	return strings.Join(labelValues, "/")
+}
+
+func (g *stateGauge) set(key string, value float64, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if old, ok := g.labels[key]; ok && getKey(old...) != getKey(labelValues...) {
+		g.vec.DeleteLabelValues(old...)
+	}
+	g.labels[key] = labelValues
+	g.vec.WithLabelValues(labelValues...).Set(value)
+}
+
+func (g *stateGauge) delete(key string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if old, ok := g.labels[key]; ok {
+		g.vec.DeleteLabelValues(old...)
+		delete(g.labels, key)
+	}
+}
+
+// deletePrefix removes the series of all the keys starting with the prefix.
+func (g *stateGauge) deletePrefix(prefix string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for key, old := range g.labels {
+		if key == prefix || strings.HasPrefix(key, prefix+"/") {
+			g.vec.DeleteLabelValues(old...)
+			delete(g.labels, key)
+		}
+	}
+}
+
+// ReportClusterState reports the state and the update state of the cluster.
+func ReportClusterState(resource *ytv1.Ytsaurus) {
+	key := getKey(resource.Namespace, resource.Name)
+	clusterState.set(key, 1, resource.Namespace, resource.Name, string(resource.Status.State))
+
+	status := resource.Status.UpdateStatus
+	if resource.Status.State != ytv1.ClusterStateUpdating || status.State == ytv1.UpdateStateNone {
+		updateState.delete(key)
+		updateStateSeconds.delete(key)
+		return
+	}
+
+	updateState.set(key, 1, resource.Namespace, resource.Name, string(status.State))
+	if status.StateStartTime != nil {
+		updateStateSeconds.set(key, time.Since(status.StateStartTime.Time).Seconds(),
+			resource.Namespace, resource.Name, string(status.State))
+	}
+}
+
+// ObserveUpdateStateDuration records the time spent in the finished update state.
+func ObserveUpdateStateDuration(resource *ytv1.Ytsaurus, state ytv1.UpdateState, duration time.Duration) {
+	updateStateDuration.WithLabelValues(resource.Namespace, resource.Name, string(state)).Observe(duration.Seconds())
+}
+
+// ReportComponentSyncStatus reports the current sync status of the component.
+func ReportComponentSyncStatus(resource *ytv1.Ytsaurus, component, syncStatus string) {
+	componentSyncStatus.set(getKey(resource.Namespace, resource.Name, component), 1,
+		resource.Namespace, resource.Name, component, syncStatus)
+}
+
+// ReportComponentSyncError counts the failed sync of the component.
+func ReportComponentSyncError(resource *ytv1.Ytsaurus, component string) {
+	componentSyncErrors.WithLabelValues(resource.Namespace, resource.Name, component).Inc()
+}
+
+// ObserveInitJobDuration records the duration of the completed init job.
+func ObserveInitJobDuration(namespace, name, component, job string, duration time.Duration) {
+	initJobDuration.WithLabelValues(namespace, name, component, job).Observe(duration.Seconds())
+}
+
+// ReportInitJobFailures reports the number of failed pods of the init job.
+func ReportInitJobFailures(namespace, name, component, job string, failures int32) {
+	initJobFailures.set(getKey(namespace, name, component, job), float64(failures), namespace, name, component, job)
+}
+
+// DeleteCluster removes the state series of the deleted cluster.
+func DeleteCluster(namespace, name string) {
+	key := getKey(namespace, name)
+	for _, gauge := range []*stateGauge{clusterState, updateState, updateStateSeconds, componentSyncStatus, initJobFailures} {
+		gauge.deletePrefix(key)
+	}
+}
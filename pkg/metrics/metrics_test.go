@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ytv1 "github.com/ytsaurus/yt-k8s-operator/api/v1"
+)
+
+func newTestYtsaurus(state ytv1.ClusterState, updateState ytv1.UpdateState) *ytv1.Ytsaurus {
+	now := metav1.Now()
+	return &ytv1.Ytsaurus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ytsaurus",
+			Namespace: "metrics-test",
+		},
+		Status: ytv1.YtsaurusStatus{
+			State: state,
+			UpdateStatus: ytv1.UpdateStatus{
+				State:          updateState,
+				StateStartTime: &now,
+			},
+		},
+	}
+}
+
+func TestReportClusterState(t *testing.T) {
+	ReportClusterState(newTestYtsaurus(ytv1.ClusterStateUpdating, ytv1.UpdateStateWaitingForPodsRemoval))
+	require.Equal(t, 1, testutil.CollectAndCount(clusterState.vec))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		clusterState.vec.WithLabelValues("metrics-test", "ytsaurus", string(ytv1.ClusterStateUpdating))))
+	require.Equal(t, 1, testutil.CollectAndCount(updateState.vec))
+	require.Equal(t, 1, testutil.CollectAndCount(updateStateSeconds.vec))
+
+	// Only the series of the current state is kept.
+	ReportClusterState(newTestYtsaurus(ytv1.ClusterStateRunning, ytv1.UpdateStateNone))
+	require.Equal(t, 1, testutil.CollectAndCount(clusterState.vec))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		clusterState.vec.WithLabelValues("metrics-test", "ytsaurus", string(ytv1.ClusterStateRunning))))
+	require.Equal(t, 0, testutil.CollectAndCount(updateState.vec))
+	require.Equal(t, 0, testutil.CollectAndCount(updateStateSeconds.vec))
+
+	DeleteCluster("metrics-test", "ytsaurus")
+	require.Equal(t, 0, testutil.CollectAndCount(clusterState.vec))
+}
+
+func TestReportComponentSyncStatus(t *testing.T) {
+	resource := newTestYtsaurus(ytv1.ClusterStateRunning, ytv1.UpdateStateNone)
+	ReportComponentSyncStatus(resource, "Discovery", "Pending")
+	ReportComponentSyncStatus(resource, "Discovery", "Ready")
+	ReportComponentSyncStatus(resource, "Master", "Ready")
+	require.Equal(t, 2, testutil.CollectAndCount(componentSyncStatus.vec))
+
+	// Clusters sharing a name prefix are kept.
+	other := newTestYtsaurus(ytv1.ClusterStateRunning, ytv1.UpdateStateNone)
+	other.Name = "ytsaurus-other"
+	ReportComponentSyncStatus(other, "Master", "Ready")
+
+	DeleteCluster("metrics-test", "ytsaurus")
+	require.Equal(t, 1, testutil.CollectAndCount(componentSyncStatus.vec))
+	DeleteCluster("metrics-test", "ytsaurus-other")
+	require.Equal(t, 0, testutil.CollectAndCount(componentSyncStatus.vec))
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.ytsaurus.tech/yt/go/ypath"
+	"go.ytsaurus.tech/yt/go/yt"
+)
+
+// instrumentedClient measures the latency and the errors of the requests the operator makes to the cluster.
+// Methods which are not used by the operator are passed through without measurement.
+type instrumentedClient struct {
+	yt.Client
+	namespace string
+	name      string
+}
+
+// NewInstrumentedClient wraps the client of the cluster with the given namespace and name.
+func NewInstrumentedClient(client yt.Client, namespace, name string) yt.Client {
+	return &instrumentedClient{
+		Client:    client,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+func (c *instrumentedClient) observe(method string, start time.Time, err error) {
+	ytClientRequestDuration.WithLabelValues(c.namespace, c.name, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		ytClientRequestErrors.WithLabelValues(c.namespace, c.name, method).Inc()
+	}
+}
+
+func (c *instrumentedClient) CreateNode(
+	ctx context.Context,
+	path ypath.YPath,
+	typ yt.NodeType,
+	options *yt.CreateNodeOptions,
+) (id yt.NodeID, err error) {
+	start := time.Now()
+	defer func() { c.observe("CreateNode", start, err) }()
+	return c.Client.CreateNode(ctx, path, typ, options)
+}
+
+func (c *instrumentedClient) CreateObject(
+	ctx context.Context,
+	typ yt.NodeType,
+	options *yt.CreateObjectOptions,
+) (id yt.NodeID, err error) {
+	start := time.Now()
+	defer func() { c.observe("CreateObject", start, err) }()
+	return c.Client.CreateObject(ctx, typ, options)
+}
+
+func (c *instrumentedClient) NodeExists(
+	ctx context.Context,
+	path ypath.YPath,
+	options *yt.NodeExistsOptions,
+) (ok bool, err error) {
+	start := time.Now()
+	defer func() { c.observe("NodeExists", start, err) }()
+	return c.Client.NodeExists(ctx, path, options)
+}
+
+func (c *instrumentedClient) RemoveNode(
+	ctx context.Context,
+	path ypath.YPath,
+	options *yt.RemoveNodeOptions,
+) (err error) {
+	start := time.Now()
+	defer func() { c.observe("RemoveNode", start, err) }()
+	return c.Client.RemoveNode(ctx, path, options)
+}
+
+func (c *instrumentedClient) GetNode(
+	ctx context.Context,
+	path ypath.YPath,
+	result any,
+	options *yt.GetNodeOptions,
+) (err error) {
+	start := time.Now()
+	defer func() { c.observe("GetNode", start, err) }()
+	return c.Client.GetNode(ctx, path, result, options)
+}
+
+func (c *instrumentedClient) SetNode(
+	ctx context.Context,
+	path ypath.YPath,
+	value any,
+	options *yt.SetNodeOptions,
+) (err error) {
+	start := time.Now()
+	defer func() { c.observe("SetNode", start, err) }()
+	return c.Client.SetNode(ctx, path, value, options)
+}
+
+func (c *instrumentedClient) ListNode(
+	ctx context.Context,
+	path ypath.YPath,
+	result any,
+	options *yt.ListNodeOptions,
+) (err error) {
+	start := time.Now()
+	defer func() { c.observe("ListNode", start, err) }()
+	return c.Client.ListNode(ctx, path, result, options)
+}
+
+func (c *instrumentedClient) CopyNode(
+	ctx context.Context,
+	src ypath.YPath,
+	dst ypath.YPath,
+	options *yt.CopyNodeOptions,
+) (id yt.NodeID, err error) {
+	start := time.Now()
+	defer func() { c.observe("CopyNode", start, err) }()
+	return c.Client.CopyNode(ctx, src, dst, options)
+}
+
+func (c *instrumentedClient) AddMember(
+	ctx context.Context,
+	group string,
+	member string,
+	options *yt.AddMemberOptions,
+) (err error) {
+	start := time.Now()
+	defer func() { c.observe("AddMember", start, err) }()
+	return c.Client.AddMember(ctx, group, member, options)
+}
+
+func (c *instrumentedClient) BuildMasterSnapshots(
+	ctx context.Context,
+	options *yt.BuildMasterSnapshotsOptions,
+) (result *yt.BuildMasterSnapshotsResponse, err error) {
+	start := time.Now()
+	defer func() { c.observe("BuildMasterSnapshots", start, err) }()
+	return c.Client.BuildMasterSnapshots(ctx, options)
+}
@@ -131,6 +131,16 @@ func (d *Deployment) ArePodsReady(ctx context.Context) bool {
 	return true
 }
 
+// GetReadyReplicas returns the number of ready pods of the deployment.
+func (d *Deployment) GetReadyReplicas() int32 {
+	return d.oldObject.Status.ReadyReplicas
+}
+
+// GetUpdatedReplicas returns the number of pods created from the current pod template.
+func (d *Deployment) GetUpdatedReplicas() int32 {
+	return d.oldObject.Status.UpdatedReplicas
+}
+
 func (d *Deployment) Fetch(ctx context.Context) error {
 	return d.ytsaurus.APIProxy().FetchObject(ctx, d.name, &d.oldObject)
 }
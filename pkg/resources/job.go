@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,6 +40,19 @@ func (j *Job) Completed() bool {
 	return j.oldObject.Status.Succeeded > 0
 }
 
+func (j *Job) Failed() int32 {
+	return j.oldObject.Status.Failed
+}
+
+// Duration returns the time the completed job took to run.
+func (j *Job) Duration() (time.Duration, bool) {
+	status := j.oldObject.Status
+	if status.StartTime == nil || status.CompletionTime == nil {
+		return 0, false
+	}
+	return status.CompletionTime.Sub(status.StartTime.Time), true
+}
+
 func (j *Job) Sync(ctx context.Context) error {
 	return j.apiProxy.SyncObject(ctx, &j.oldObject, &j.newObject)
 }
@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ytsaurus/yt-k8s-operator/pkg/apiproxy"
+	"github.com/ytsaurus/yt-k8s-operator/pkg/labeller"
+)
+
+type PodDisruptionBudget struct {
+	name     string
+	labeller *labeller.Labeller
+	apiProxy apiproxy.APIProxy
+
+	oldObject policyv1.PodDisruptionBudget
+	newObject policyv1.PodDisruptionBudget
+}
+
+func NewPodDisruptionBudget(labeller *labeller.Labeller, apiProxy apiproxy.APIProxy) *PodDisruptionBudget {
+	return &PodDisruptionBudget{
+		name:     fmt.Sprintf("%s-pdb", labeller.ComponentLabel),
+		labeller: labeller,
+		apiProxy: apiProxy,
+	}
+}
+
+func (b *PodDisruptionBudget) OldObject() client.Object {
+	return &b.oldObject
+}
+
+func (b *PodDisruptionBudget) Name() string {
+	return b.name
+}
+
+func (b *PodDisruptionBudget) Sync(ctx context.Context) error {
+	return b.apiProxy.SyncObject(ctx, &b.oldObject, &b.newObject)
+}
+
+// Build builds the budget of the component pods, exactly one of minAvailable and maxUnavailable is expected to be set.
+func (b *PodDisruptionBudget) Build(minAvailable, maxUnavailable *intstr.IntOrString) *policyv1.PodDisruptionBudget {
+	b.newObject.ObjectMeta = b.labeller.GetObjectMeta(b.name)
+	b.newObject.Spec = policyv1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: b.labeller.GetSelectorLabelMap(),
+		},
+		MinAvailable:   minAvailable,
+		MaxUnavailable: maxUnavailable,
+	}
+
+	return &b.newObject
+}
+
+// NeedSync checks that the existing budget differs from the given one.
+func (b *PodDisruptionBudget) NeedSync(minAvailable, maxUnavailable *intstr.IntOrString) bool {
+	return !reflect.DeepEqual(b.oldObject.Spec.MinAvailable, minAvailable) ||
+		!reflect.DeepEqual(b.oldObject.Spec.MaxUnavailable, maxUnavailable)
+}
+
+func (b *PodDisruptionBudget) Fetch(ctx context.Context) error {
+	return b.apiProxy.FetchObject(ctx, b.name, &b.oldObject)
+}
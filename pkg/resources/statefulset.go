@@ -96,6 +96,19 @@ func (s *StatefulSet) ArePodsRemoved(ctx context.Context) bool {
 	return true
 }
 
+// IsPodReady checks that the pod is running and its containers passed the readiness probes.
+func IsPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (s *StatefulSet) ArePodsReady(ctx context.Context, minReadyInstanceCount *int) bool {
 	logger := log.FromContext(ctx)
 	podList := s.getPods(ctx)
@@ -110,8 +123,8 @@ func (s *StatefulSet) ArePodsReady(ctx context.Context, minReadyInstanceCount *i
 
 	readyInstanceCount := 0
 	for _, pod := range podList.Items {
-		if pod.Status.Phase != corev1.PodRunning {
-			logger.Info("pod is not yet running", "podName", pod.Name, "phase", pod.Status.Phase)
+		if !IsPodReady(&pod) {
+			logger.Info("pod is not yet ready", "podName", pod.Name, "phase", pod.Status.Phase)
 		} else {
 			readyInstanceCount += 1
 		}
@@ -119,7 +132,7 @@ func (s *StatefulSet) ArePodsReady(ctx context.Context, minReadyInstanceCount *i
 
 	if readyInstanceCount < effectiveMinReadyInstanceCount {
 		logger.Info(
-			"not enough pods are running",
+			"not enough pods are ready",
 			"component", s.labeller.ComponentName,
 			"readyInstanceCount", readyInstanceCount,
 			"minReadyInstanceCount", effectiveMinReadyInstanceCount,
@@ -130,6 +143,45 @@ func (s *StatefulSet) ArePodsReady(ctx context.Context, minReadyInstanceCount *i
 	return true
 }
 
+// GetRollingUpdatePartition returns the partition of the current rolling update strategy,
+// pods with ordinal lower than the partition are not updated.
+func (s *StatefulSet) GetRollingUpdatePartition() int32 {
+	rollingUpdate := s.oldObject.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.Partition == nil {
+		return 0
+	}
+	return *rollingUpdate.Partition
+}
+
+// GetReplicas returns the number of replicas of the existing statefulset.
+func (s *StatefulSet) GetReplicas() int32 {
+	if s.oldObject.Spec.Replicas == nil {
+		return 0
+	}
+	return *s.oldObject.Spec.Replicas
+}
+
+// GetUpdatedReplicas returns the number of pods created from the current pod template.
+func (s *StatefulSet) GetUpdatedReplicas() int32 {
+	return s.oldObject.Status.UpdatedReplicas
+}
+
+// GetReadyReplicas returns the number of ready pods of the statefulset.
+func (s *StatefulSet) GetReadyReplicas() int32 {
+	return s.oldObject.Status.ReadyReplicas
+}
+
+// AreUpdatedPodsReady checks that all pods with ordinal not lower than partition
+// are created from the current pod template and all pods of the statefulset are ready.
+func (s *StatefulSet) AreUpdatedPodsReady(replicas, partition int32) bool {
+	status := s.oldObject.Status
+	if status.ObservedGeneration < s.oldObject.Generation {
+		return false
+	}
+
+	return status.UpdatedReplicas >= replicas-partition && status.ReadyReplicas >= replicas
+}
+
 func (s *StatefulSet) NeedSync(replicas int32) bool {
 	if s.ytsaurus.GetClusterState() != v1.ClusterStateInitializing {
 		if s.labeller.NeedSync(s.oldObject.ObjectMeta) {
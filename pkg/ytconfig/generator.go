@@ -158,6 +158,39 @@ func (g *Generator) getDiscoveryAddresses() []string {
 	return names
 }
 
+// GetDataNodeAddress returns the address of the data node pod with the given ordinal.
+func (g *Generator) GetDataNodeAddress(name string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d.%s.%s.svc.%s:%d",
+		g.GetDataNodesStatefulSetName(name),
+		ordinal,
+		g.GetDataNodesServiceName(name),
+		g.ytsaurus.Namespace,
+		g.clusterDomain,
+		consts.DataNodeRPCPort)
+}
+
+// GetExecNodeAddress returns the address of the exec node pod with the given ordinal.
+func (g *Generator) GetExecNodeAddress(name string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d.%s.%s.svc.%s:%d",
+		g.GetExecNodesStatefulSetName(name),
+		ordinal,
+		g.GetExecNodesServiceName(name),
+		g.ytsaurus.Namespace,
+		g.clusterDomain,
+		consts.ExecNodeRPCPort)
+}
+
+// GetTabletNodeAddress returns the address of the tablet node pod with the given ordinal.
+func (g *Generator) GetTabletNodeAddress(name string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d.%s.%s.svc.%s:%d",
+		g.GetTabletNodesStatefulSetName(name),
+		ordinal,
+		g.GetTabletNodesServiceName(name),
+		g.ytsaurus.Namespace,
+		g.clusterDomain,
+		consts.TabletNodeRPCPort)
+}
+
 func (g *Generator) GetYQLAgentAddresses() []string {
 	names := make([]string, 0, g.ytsaurus.Spec.YQLAgents.InstanceCount)
 	for _, podName := range g.GetYQLAgentPodNames() {